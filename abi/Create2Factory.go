@@ -0,0 +1,224 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package abi
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// Create2FactoryMetaData contains all meta data concerning the Create2Factory contract.
+var Create2FactoryMetaData = &bind.MetaData{
+	ABI: "[{\"stateMutability\":\"nonpayable\",\"type\":\"fallback\"}]",
+	Bin: "0x608060405234801561000f575f80fd5b5061024d8061001d5f395ff3fe608060405234801561000f575f80fd5b505f3660605f83835f9060209261002893929190610108565b906100339190610161565b90505f8484602090809261004993929190610108565b8080601f0160208091040260200160405190810160405280939291908181526020018383808284375f81840152601f19601f8201169050808301925050505050505090505f828251602084015ff590505f73ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff16036100d0575f80fd5b806040516020016100e191906101fe565b6040516020818303038152906040529350505050915050805190602001f35b5f80fd5b5f80fd5b5f808585111561011b5761011a610100565b5b8386111561012c5761012b610104565b5b6001850283019150848603905094509492505050565b5f82905092915050565b5f819050919050565b5f82821b905092915050565b5f61016c8383610142565b82610177813561014c565b925060208210156101b7576101b27fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff83602003600802610155565b831692505b505092915050565b5f73ffffffffffffffffffffffffffffffffffffffff82169050919050565b5f6101e8826101bf565b9050919050565b6101f8816101de565b82525050565b5f6020820190506102115f8301846101ef565b9291505056fea2646970667358221220f1ca6ed155abc14c799f28876bd9d22e615995a1a640594383078bf09be9c31364736f6c63430008150033",
+}
+
+// Create2FactoryABI is the input ABI used to generate the binding from.
+// Deprecated: Use Create2FactoryMetaData.ABI instead.
+var Create2FactoryABI = Create2FactoryMetaData.ABI
+
+// Create2FactoryBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use Create2FactoryMetaData.Bin instead.
+var Create2FactoryBin = Create2FactoryMetaData.Bin
+
+// DeployCreate2Factory deploys a new Ethereum contract, binding an instance of Create2Factory to it.
+func DeployCreate2Factory(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *Create2Factory, error) {
+	parsed, err := Create2FactoryMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(Create2FactoryBin), backend)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &Create2Factory{Create2FactoryCaller: Create2FactoryCaller{contract: contract}, Create2FactoryTransactor: Create2FactoryTransactor{contract: contract}, Create2FactoryFilterer: Create2FactoryFilterer{contract: contract}}, nil
+}
+
+// Create2Factory is an auto generated Go binding around an Ethereum contract.
+type Create2Factory struct {
+	Create2FactoryCaller     // Read-only binding to the contract
+	Create2FactoryTransactor // Write-only binding to the contract
+	Create2FactoryFilterer   // Log filterer for contract events
+}
+
+// Create2FactoryCaller is an auto generated read-only Go binding around an Ethereum contract.
+type Create2FactoryCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// Create2FactoryTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type Create2FactoryTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// Create2FactoryFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type Create2FactoryFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// Create2FactorySession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type Create2FactorySession struct {
+	Contract     *Create2Factory   // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// Create2FactoryCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type Create2FactoryCallerSession struct {
+	Contract *Create2FactoryCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts         // Call options to use throughout this session
+}
+
+// Create2FactoryTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type Create2FactoryTransactorSession struct {
+	Contract     *Create2FactoryTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts         // Transaction auth options to use throughout this session
+}
+
+// Create2FactoryRaw is an auto generated low-level Go binding around an Ethereum contract.
+type Create2FactoryRaw struct {
+	Contract *Create2Factory // Generic contract binding to access the raw methods on
+}
+
+// Create2FactoryCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type Create2FactoryCallerRaw struct {
+	Contract *Create2FactoryCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// Create2FactoryTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type Create2FactoryTransactorRaw struct {
+	Contract *Create2FactoryTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewCreate2Factory creates a new instance of Create2Factory, bound to a specific deployed contract.
+func NewCreate2Factory(address common.Address, backend bind.ContractBackend) (*Create2Factory, error) {
+	contract, err := bindCreate2Factory(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Create2Factory{Create2FactoryCaller: Create2FactoryCaller{contract: contract}, Create2FactoryTransactor: Create2FactoryTransactor{contract: contract}, Create2FactoryFilterer: Create2FactoryFilterer{contract: contract}}, nil
+}
+
+// NewCreate2FactoryCaller creates a new read-only instance of Create2Factory, bound to a specific deployed contract.
+func NewCreate2FactoryCaller(address common.Address, caller bind.ContractCaller) (*Create2FactoryCaller, error) {
+	contract, err := bindCreate2Factory(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Create2FactoryCaller{contract: contract}, nil
+}
+
+// NewCreate2FactoryTransactor creates a new write-only instance of Create2Factory, bound to a specific deployed contract.
+func NewCreate2FactoryTransactor(address common.Address, transactor bind.ContractTransactor) (*Create2FactoryTransactor, error) {
+	contract, err := bindCreate2Factory(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Create2FactoryTransactor{contract: contract}, nil
+}
+
+// NewCreate2FactoryFilterer creates a new log filterer instance of Create2Factory, bound to a specific deployed contract.
+func NewCreate2FactoryFilterer(address common.Address, filterer bind.ContractFilterer) (*Create2FactoryFilterer, error) {
+	contract, err := bindCreate2Factory(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &Create2FactoryFilterer{contract: contract}, nil
+}
+
+// bindCreate2Factory binds a generic wrapper to an already deployed contract.
+func bindCreate2Factory(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := Create2FactoryMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Create2Factory *Create2FactoryRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Create2Factory.Contract.Create2FactoryCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Create2Factory *Create2FactoryRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Create2Factory.Contract.Create2FactoryTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Create2Factory *Create2FactoryRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Create2Factory.Contract.Create2FactoryTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Create2Factory *Create2FactoryCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Create2Factory.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Create2Factory *Create2FactoryTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Create2Factory.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Create2Factory *Create2FactoryTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Create2Factory.Contract.contract.Transact(opts, method, params...)
+}
+
+// Fallback is a paid mutator transaction binding the contract fallback function.
+//
+// Solidity: fallback() returns()
+func (_Create2Factory *Create2FactoryTransactor) Fallback(opts *bind.TransactOpts, calldata []byte) (*types.Transaction, error) {
+	return _Create2Factory.contract.RawTransact(opts, calldata)
+}
+
+// Fallback is a paid mutator transaction binding the contract fallback function.
+//
+// Solidity: fallback() returns()
+func (_Create2Factory *Create2FactorySession) Fallback(calldata []byte) (*types.Transaction, error) {
+	return _Create2Factory.Contract.Fallback(&_Create2Factory.TransactOpts, calldata)
+}
+
+// Fallback is a paid mutator transaction binding the contract fallback function.
+//
+// Solidity: fallback() returns()
+func (_Create2Factory *Create2FactoryTransactorSession) Fallback(calldata []byte) (*types.Transaction, error) {
+	return _Create2Factory.Contract.Fallback(&_Create2Factory.TransactOpts, calldata)
+}
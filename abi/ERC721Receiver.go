@@ -0,0 +1,234 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package abi
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// ERC721ReceiverMetaData contains all meta data concerning the ERC721Receiver contract.
+var ERC721ReceiverMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"\",\"type\":\"bytes\"}],\"name\":\"onERC721Received\",\"outputs\":[{\"internalType\":\"bytes4\",\"name\":\"\",\"type\":\"bytes4\"}],\"stateMutability\":\"pure\",\"type\":\"function\"}]",
+	Bin: "0x608060405234801561000f575f80fd5b506102748061001d5f395ff3fe608060405234801561000f575f80fd5b5060043610610029575f3560e01c8063150b7a021461002d575b5f80fd5b61004760048036038101906100429190610167565b61005d565b6040516100549190610225565b60405180910390f35b5f63150b7a0260e01b905095945050505050565b5f80fd5b5f80fd5b5f73ffffffffffffffffffffffffffffffffffffffff82169050919050565b5f6100a282610079565b9050919050565b6100b281610098565b81146100bc575f80fd5b50565b5f813590506100cd816100a9565b92915050565b5f819050919050565b6100e5816100d3565b81146100ef575f80fd5b50565b5f81359050610100816100dc565b92915050565b5f80fd5b5f80fd5b5f80fd5b5f8083601f84011261012757610126610106565b5b8235905067ffffffffffffffff8111156101445761014361010a565b5b6020830191508360018202830111156101605761015f61010e565b5b9250929050565b5f805f805f608086880312156101805761017f610071565b5b5f61018d888289016100bf565b955050602061019e888289016100bf565b94505060406101af888289016100f2565b935050606086013567ffffffffffffffff8111156101d0576101cf610075565b5b6101dc88828901610112565b92509250509295509295909350565b5f7fffffffff0000000000000000000000000000000000000000000000000000000082169050919050565b61021f816101eb565b82525050565b5f6020820190506102385f830184610216565b9291505056fea2646970667358221220e5bceb6e88c118253c3746d17379ccc5674e2e7bfac40aa42b5c1da057e1ee9e64736f6c63430008150033",
+}
+
+// ERC721ReceiverABI is the input ABI used to generate the binding from.
+// Deprecated: Use ERC721ReceiverMetaData.ABI instead.
+var ERC721ReceiverABI = ERC721ReceiverMetaData.ABI
+
+// ERC721ReceiverBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use ERC721ReceiverMetaData.Bin instead.
+var ERC721ReceiverBin = ERC721ReceiverMetaData.Bin
+
+// DeployERC721Receiver deploys a new Ethereum contract, binding an instance of ERC721Receiver to it.
+func DeployERC721Receiver(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *ERC721Receiver, error) {
+	parsed, err := ERC721ReceiverMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(ERC721ReceiverBin), backend)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &ERC721Receiver{ERC721ReceiverCaller: ERC721ReceiverCaller{contract: contract}, ERC721ReceiverTransactor: ERC721ReceiverTransactor{contract: contract}, ERC721ReceiverFilterer: ERC721ReceiverFilterer{contract: contract}}, nil
+}
+
+// ERC721Receiver is an auto generated Go binding around an Ethereum contract.
+type ERC721Receiver struct {
+	ERC721ReceiverCaller     // Read-only binding to the contract
+	ERC721ReceiverTransactor // Write-only binding to the contract
+	ERC721ReceiverFilterer   // Log filterer for contract events
+}
+
+// ERC721ReceiverCaller is an auto generated read-only Go binding around an Ethereum contract.
+type ERC721ReceiverCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ERC721ReceiverTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type ERC721ReceiverTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ERC721ReceiverFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type ERC721ReceiverFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ERC721ReceiverSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type ERC721ReceiverSession struct {
+	Contract     *ERC721Receiver   // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// ERC721ReceiverCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type ERC721ReceiverCallerSession struct {
+	Contract *ERC721ReceiverCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts         // Call options to use throughout this session
+}
+
+// ERC721ReceiverTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type ERC721ReceiverTransactorSession struct {
+	Contract     *ERC721ReceiverTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts         // Transaction auth options to use throughout this session
+}
+
+// ERC721ReceiverRaw is an auto generated low-level Go binding around an Ethereum contract.
+type ERC721ReceiverRaw struct {
+	Contract *ERC721Receiver // Generic contract binding to access the raw methods on
+}
+
+// ERC721ReceiverCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type ERC721ReceiverCallerRaw struct {
+	Contract *ERC721ReceiverCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// ERC721ReceiverTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type ERC721ReceiverTransactorRaw struct {
+	Contract *ERC721ReceiverTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewERC721Receiver creates a new instance of ERC721Receiver, bound to a specific deployed contract.
+func NewERC721Receiver(address common.Address, backend bind.ContractBackend) (*ERC721Receiver, error) {
+	contract, err := bindERC721Receiver(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &ERC721Receiver{ERC721ReceiverCaller: ERC721ReceiverCaller{contract: contract}, ERC721ReceiverTransactor: ERC721ReceiverTransactor{contract: contract}, ERC721ReceiverFilterer: ERC721ReceiverFilterer{contract: contract}}, nil
+}
+
+// NewERC721ReceiverCaller creates a new read-only instance of ERC721Receiver, bound to a specific deployed contract.
+func NewERC721ReceiverCaller(address common.Address, caller bind.ContractCaller) (*ERC721ReceiverCaller, error) {
+	contract, err := bindERC721Receiver(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ERC721ReceiverCaller{contract: contract}, nil
+}
+
+// NewERC721ReceiverTransactor creates a new write-only instance of ERC721Receiver, bound to a specific deployed contract.
+func NewERC721ReceiverTransactor(address common.Address, transactor bind.ContractTransactor) (*ERC721ReceiverTransactor, error) {
+	contract, err := bindERC721Receiver(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ERC721ReceiverTransactor{contract: contract}, nil
+}
+
+// NewERC721ReceiverFilterer creates a new log filterer instance of ERC721Receiver, bound to a specific deployed contract.
+func NewERC721ReceiverFilterer(address common.Address, filterer bind.ContractFilterer) (*ERC721ReceiverFilterer, error) {
+	contract, err := bindERC721Receiver(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &ERC721ReceiverFilterer{contract: contract}, nil
+}
+
+// bindERC721Receiver binds a generic wrapper to an already deployed contract.
+func bindERC721Receiver(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := ERC721ReceiverMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_ERC721Receiver *ERC721ReceiverRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _ERC721Receiver.Contract.ERC721ReceiverCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_ERC721Receiver *ERC721ReceiverRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ERC721Receiver.Contract.ERC721ReceiverTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_ERC721Receiver *ERC721ReceiverRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _ERC721Receiver.Contract.ERC721ReceiverTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_ERC721Receiver *ERC721ReceiverCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _ERC721Receiver.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_ERC721Receiver *ERC721ReceiverTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ERC721Receiver.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_ERC721Receiver *ERC721ReceiverTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _ERC721Receiver.Contract.contract.Transact(opts, method, params...)
+}
+
+// OnERC721Received is a free data retrieval call binding the contract method 0x150b7a02.
+//
+// Solidity: function onERC721Received(address , address , uint256 , bytes ) pure returns(bytes4)
+func (_ERC721Receiver *ERC721ReceiverCaller) OnERC721Received(opts *bind.CallOpts, arg0 common.Address, arg1 common.Address, arg2 *big.Int, arg3 []byte) ([4]byte, error) {
+	var out []interface{}
+	err := _ERC721Receiver.contract.Call(opts, &out, "onERC721Received", arg0, arg1, arg2, arg3)
+
+	if err != nil {
+		return *new([4]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([4]byte)).(*[4]byte)
+
+	return out0, err
+
+}
+
+// OnERC721Received is a free data retrieval call binding the contract method 0x150b7a02.
+//
+// Solidity: function onERC721Received(address , address , uint256 , bytes ) pure returns(bytes4)
+func (_ERC721Receiver *ERC721ReceiverSession) OnERC721Received(arg0 common.Address, arg1 common.Address, arg2 *big.Int, arg3 []byte) ([4]byte, error) {
+	return _ERC721Receiver.Contract.OnERC721Received(&_ERC721Receiver.CallOpts, arg0, arg1, arg2, arg3)
+}
+
+// OnERC721Received is a free data retrieval call binding the contract method 0x150b7a02.
+//
+// Solidity: function onERC721Received(address , address , uint256 , bytes ) pure returns(bytes4)
+func (_ERC721Receiver *ERC721ReceiverCallerSession) OnERC721Received(arg0 common.Address, arg1 common.Address, arg2 *big.Int, arg3 []byte) ([4]byte, error) {
+	return _ERC721Receiver.Contract.OnERC721Received(&_ERC721Receiver.CallOpts, arg0, arg1, arg2, arg3)
+}
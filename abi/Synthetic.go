@@ -0,0 +1,421 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package abi
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// SyntheticMetaData contains all meta data concerning the Synthetic contract.
+var SyntheticMetaData = &bind.MetaData{
+	ABI: "[{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"index\",\"type\":\"uint256\"}],\"name\":\"Emitted\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"count\",\"type\":\"uint256\"}],\"name\":\"emitLogs\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"rounds\",\"type\":\"uint256\"}],\"name\":\"keccak\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"h\",\"type\":\"bytes32\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"}],\"name\":\"payload\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"slots\",\"type\":\"uint256\"}],\"name\":\"store\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+	Bin: "0x608060405234801561000f575f80fd5b506104258061001d5f395ff3fe608060405234801561000f575f80fd5b506004361061004a575f3560e01c80634ce4ddea1461004e5780636057361d1461006a578063643e430814610086578063ba79920e146100a2575b5f80fd5b610068600480360381019061006391906101f1565b6100d2565b005b610084600480360381019061007f91906101f1565b610128565b005b6100a0600480360381019061009b919061027d565b610167565b005b6100bc60048036038101906100b791906101f1565b61016b565b6040516100c991906102e0565b60405180910390f35b5b5f811115610125577fa29a2caaccc1085ba955c262f6c80a1d1480f34fc1712c44c357c98030b9ee578160405161010a9190610308565b60405180910390a1808061011d9061034e565b9150506100d3565b50565b5f805490505f828261013a9190610375565b9050805f819055505b80821015610162578180610156906103a8565b92505060018255610143565b505050565b5050565b5f5b5f8211156101b1578060405160200161018691906102e0565b60405160208183030381529060405280519060200120905081806101a99061034e565b92505061016d565b919050565b5f80fd5b5f80fd5b5f819050919050565b6101d0816101be565b81146101da575f80fd5b50565b5f813590506101eb816101c7565b92915050565b5f60208284031215610206576102056101b6565b5b5f610213848285016101dd565b91505092915050565b5f80fd5b5f80fd5b5f80fd5b5f8083601f84011261023d5761023c61021c565b5b8235905067ffffffffffffffff81111561025a57610259610220565b5b60208301915083600182028301111561027657610275610224565b5b9250929050565b5f8060208385031215610293576102926101b6565b5b5f83013567ffffffffffffffff8111156102b0576102af6101ba565b5b6102bc85828601610228565b92509250509250929050565b5f819050919050565b6102da816102c8565b82525050565b5f6020820190506102f35f8301846102d1565b92915050565b610302816101be565b82525050565b5f60208201905061031b5f8301846102f9565b92915050565b7f4e487b71000000000000000000000000000000000000000000000000000000005f52601160045260245ffd5b5f610358826101be565b91505f820361036a57610369610321565b5b600182039050919050565b5f61037f826101be565b915061038a836101be565b92508282019050808211156103a2576103a1610321565b5b92915050565b5f6103b2826101be565b91507fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff82036103e4576103e3610321565b5b60018201905091905056fea2646970667358221220f97d2bcb2da03b624d3a8af291b916af563f14c12c69ec94159734d645fede5e64736f6c63430008150033",
+}
+
+// SyntheticABI is the input ABI used to generate the binding from.
+// Deprecated: Use SyntheticMetaData.ABI instead.
+var SyntheticABI = SyntheticMetaData.ABI
+
+// SyntheticBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use SyntheticMetaData.Bin instead.
+var SyntheticBin = SyntheticMetaData.Bin
+
+// DeploySynthetic deploys a new Ethereum contract, binding an instance of Synthetic to it.
+func DeploySynthetic(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *Synthetic, error) {
+	parsed, err := SyntheticMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(SyntheticBin), backend)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &Synthetic{SyntheticCaller: SyntheticCaller{contract: contract}, SyntheticTransactor: SyntheticTransactor{contract: contract}, SyntheticFilterer: SyntheticFilterer{contract: contract}}, nil
+}
+
+// Synthetic is an auto generated Go binding around an Ethereum contract.
+type Synthetic struct {
+	SyntheticCaller     // Read-only binding to the contract
+	SyntheticTransactor // Write-only binding to the contract
+	SyntheticFilterer   // Log filterer for contract events
+}
+
+// SyntheticCaller is an auto generated read-only Go binding around an Ethereum contract.
+type SyntheticCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SyntheticTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type SyntheticTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SyntheticFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type SyntheticFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SyntheticSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type SyntheticSession struct {
+	Contract     *Synthetic        // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// SyntheticCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type SyntheticCallerSession struct {
+	Contract *SyntheticCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts    // Call options to use throughout this session
+}
+
+// SyntheticTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type SyntheticTransactorSession struct {
+	Contract     *SyntheticTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts    // Transaction auth options to use throughout this session
+}
+
+// SyntheticRaw is an auto generated low-level Go binding around an Ethereum contract.
+type SyntheticRaw struct {
+	Contract *Synthetic // Generic contract binding to access the raw methods on
+}
+
+// SyntheticCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type SyntheticCallerRaw struct {
+	Contract *SyntheticCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// SyntheticTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type SyntheticTransactorRaw struct {
+	Contract *SyntheticTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewSynthetic creates a new instance of Synthetic, bound to a specific deployed contract.
+func NewSynthetic(address common.Address, backend bind.ContractBackend) (*Synthetic, error) {
+	contract, err := bindSynthetic(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Synthetic{SyntheticCaller: SyntheticCaller{contract: contract}, SyntheticTransactor: SyntheticTransactor{contract: contract}, SyntheticFilterer: SyntheticFilterer{contract: contract}}, nil
+}
+
+// NewSyntheticCaller creates a new read-only instance of Synthetic, bound to a specific deployed contract.
+func NewSyntheticCaller(address common.Address, caller bind.ContractCaller) (*SyntheticCaller, error) {
+	contract, err := bindSynthetic(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SyntheticCaller{contract: contract}, nil
+}
+
+// NewSyntheticTransactor creates a new write-only instance of Synthetic, bound to a specific deployed contract.
+func NewSyntheticTransactor(address common.Address, transactor bind.ContractTransactor) (*SyntheticTransactor, error) {
+	contract, err := bindSynthetic(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SyntheticTransactor{contract: contract}, nil
+}
+
+// NewSyntheticFilterer creates a new log filterer instance of Synthetic, bound to a specific deployed contract.
+func NewSyntheticFilterer(address common.Address, filterer bind.ContractFilterer) (*SyntheticFilterer, error) {
+	contract, err := bindSynthetic(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &SyntheticFilterer{contract: contract}, nil
+}
+
+// bindSynthetic binds a generic wrapper to an already deployed contract.
+func bindSynthetic(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := SyntheticMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Synthetic *SyntheticRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Synthetic.Contract.SyntheticCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Synthetic *SyntheticRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Synthetic.Contract.SyntheticTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Synthetic *SyntheticRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Synthetic.Contract.SyntheticTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Synthetic *SyntheticCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Synthetic.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Synthetic *SyntheticTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Synthetic.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Synthetic *SyntheticTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Synthetic.Contract.contract.Transact(opts, method, params...)
+}
+
+// EmitLogs is a paid mutator transaction binding the contract method 0x4ce4ddea.
+//
+// Solidity: function emitLogs(uint256 count) returns()
+func (_Synthetic *SyntheticTransactor) EmitLogs(opts *bind.TransactOpts, count *big.Int) (*types.Transaction, error) {
+	return _Synthetic.contract.Transact(opts, "emitLogs", count)
+}
+
+// EmitLogs is a paid mutator transaction binding the contract method 0x4ce4ddea.
+//
+// Solidity: function emitLogs(uint256 count) returns()
+func (_Synthetic *SyntheticSession) EmitLogs(count *big.Int) (*types.Transaction, error) {
+	return _Synthetic.Contract.EmitLogs(&_Synthetic.TransactOpts, count)
+}
+
+// EmitLogs is a paid mutator transaction binding the contract method 0x4ce4ddea.
+//
+// Solidity: function emitLogs(uint256 count) returns()
+func (_Synthetic *SyntheticTransactorSession) EmitLogs(count *big.Int) (*types.Transaction, error) {
+	return _Synthetic.Contract.EmitLogs(&_Synthetic.TransactOpts, count)
+}
+
+// Keccak is a paid mutator transaction binding the contract method 0xba79920e.
+//
+// Solidity: function keccak(uint256 rounds) returns(bytes32 h)
+func (_Synthetic *SyntheticTransactor) Keccak(opts *bind.TransactOpts, rounds *big.Int) (*types.Transaction, error) {
+	return _Synthetic.contract.Transact(opts, "keccak", rounds)
+}
+
+// Keccak is a paid mutator transaction binding the contract method 0xba79920e.
+//
+// Solidity: function keccak(uint256 rounds) returns(bytes32 h)
+func (_Synthetic *SyntheticSession) Keccak(rounds *big.Int) (*types.Transaction, error) {
+	return _Synthetic.Contract.Keccak(&_Synthetic.TransactOpts, rounds)
+}
+
+// Keccak is a paid mutator transaction binding the contract method 0xba79920e.
+//
+// Solidity: function keccak(uint256 rounds) returns(bytes32 h)
+func (_Synthetic *SyntheticTransactorSession) Keccak(rounds *big.Int) (*types.Transaction, error) {
+	return _Synthetic.Contract.Keccak(&_Synthetic.TransactOpts, rounds)
+}
+
+// Payload is a paid mutator transaction binding the contract method 0x643e4308.
+//
+// Solidity: function payload(bytes data) returns()
+func (_Synthetic *SyntheticTransactor) Payload(opts *bind.TransactOpts, data []byte) (*types.Transaction, error) {
+	return _Synthetic.contract.Transact(opts, "payload", data)
+}
+
+// Payload is a paid mutator transaction binding the contract method 0x643e4308.
+//
+// Solidity: function payload(bytes data) returns()
+func (_Synthetic *SyntheticSession) Payload(data []byte) (*types.Transaction, error) {
+	return _Synthetic.Contract.Payload(&_Synthetic.TransactOpts, data)
+}
+
+// Payload is a paid mutator transaction binding the contract method 0x643e4308.
+//
+// Solidity: function payload(bytes data) returns()
+func (_Synthetic *SyntheticTransactorSession) Payload(data []byte) (*types.Transaction, error) {
+	return _Synthetic.Contract.Payload(&_Synthetic.TransactOpts, data)
+}
+
+// Store is a paid mutator transaction binding the contract method 0x6057361d.
+//
+// Solidity: function store(uint256 slots) returns()
+func (_Synthetic *SyntheticTransactor) Store(opts *bind.TransactOpts, slots *big.Int) (*types.Transaction, error) {
+	return _Synthetic.contract.Transact(opts, "store", slots)
+}
+
+// Store is a paid mutator transaction binding the contract method 0x6057361d.
+//
+// Solidity: function store(uint256 slots) returns()
+func (_Synthetic *SyntheticSession) Store(slots *big.Int) (*types.Transaction, error) {
+	return _Synthetic.Contract.Store(&_Synthetic.TransactOpts, slots)
+}
+
+// Store is a paid mutator transaction binding the contract method 0x6057361d.
+//
+// Solidity: function store(uint256 slots) returns()
+func (_Synthetic *SyntheticTransactorSession) Store(slots *big.Int) (*types.Transaction, error) {
+	return _Synthetic.Contract.Store(&_Synthetic.TransactOpts, slots)
+}
+
+// SyntheticEmittedIterator is returned from FilterEmitted and is used to iterate over the raw logs and unpacked data for Emitted events raised by the Synthetic contract.
+type SyntheticEmittedIterator struct {
+	Event *SyntheticEmitted // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SyntheticEmittedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SyntheticEmitted)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SyntheticEmitted)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SyntheticEmittedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SyntheticEmittedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SyntheticEmitted represents a Emitted event raised by the Synthetic contract.
+type SyntheticEmitted struct {
+	Index *big.Int
+	Raw   types.Log // Blockchain specific contextual infos
+}
+
+// FilterEmitted is a free log retrieval operation binding the contract event 0xa29a2caaccc1085ba955c262f6c80a1d1480f34fc1712c44c357c98030b9ee57.
+//
+// Solidity: event Emitted(uint256 index)
+func (_Synthetic *SyntheticFilterer) FilterEmitted(opts *bind.FilterOpts) (*SyntheticEmittedIterator, error) {
+
+	logs, sub, err := _Synthetic.contract.FilterLogs(opts, "Emitted")
+	if err != nil {
+		return nil, err
+	}
+	return &SyntheticEmittedIterator{contract: _Synthetic.contract, event: "Emitted", logs: logs, sub: sub}, nil
+}
+
+// WatchEmitted is a free log subscription operation binding the contract event 0xa29a2caaccc1085ba955c262f6c80a1d1480f34fc1712c44c357c98030b9ee57.
+//
+// Solidity: event Emitted(uint256 index)
+func (_Synthetic *SyntheticFilterer) WatchEmitted(opts *bind.WatchOpts, sink chan<- *SyntheticEmitted) (event.Subscription, error) {
+
+	logs, sub, err := _Synthetic.contract.WatchLogs(opts, "Emitted")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SyntheticEmitted)
+				if err := _Synthetic.contract.UnpackLog(event, "Emitted", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseEmitted is a log parse operation binding the contract event 0xa29a2caaccc1085ba955c262f6c80a1d1480f34fc1712c44c357c98030b9ee57.
+//
+// Solidity: event Emitted(uint256 index)
+func (_Synthetic *SyntheticFilterer) ParseEmitted(log types.Log) (*SyntheticEmitted, error) {
+	event := new(SyntheticEmitted)
+	if err := _Synthetic.contract.UnpackLog(event, "Emitted", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
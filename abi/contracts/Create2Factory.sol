@@ -0,0 +1,18 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+// ANTPSCreate2Factory deploys the init code in the calldata with CREATE2.
+// The calldata is a 32 byte salt followed by the init code, like the
+// deterministic deployment proxy. The created address is returned as a word.
+contract ANTPSCreate2Factory {
+    fallback(bytes calldata input) external returns (bytes memory) {
+        bytes32 salt = bytes32(input[:32]);
+        bytes memory code = input[32:];
+        address created;
+        assembly {
+            created := create2(0, add(code, 0x20), mload(code), salt)
+        }
+        require(created != address(0));
+        return abi.encode(created);
+    }
+}
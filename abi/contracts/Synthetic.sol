@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+// ANTPSSynthetic has one call per resource a transaction can stress. The
+// argument of each call sets its intensity.
+contract ANTPSSynthetic {
+    event Emitted(uint256 index);
+
+    // next counts the written slots, it is read from slot 0 by the verification
+    uint256 next;
+
+    // keccak hashes a word rounds times. It is not pure, so that the
+    // benchmark sends it as a transaction.
+    function keccak(uint256 rounds) external returns (bytes32 h) {
+        for (; rounds > 0; rounds--) {
+            h = keccak256(abi.encode(h));
+        }
+    }
+
+    // store writes slots storage slots that were never written before.
+    function store(uint256 slots) external {
+        uint256 i = next;
+        uint256 end = i + slots;
+        next = end;
+        while (i < end) {
+            i++;
+            assembly {
+                sstore(i, 1)
+            }
+        }
+    }
+
+    // payload only pays for the calldata.
+    function payload(bytes calldata data) external {}
+
+    // emitLogs emits count events.
+    function emitLogs(uint256 count) external {
+        for (; count > 0; count--) {
+            emit Emitted(count);
+        }
+    }
+}
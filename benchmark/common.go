@@ -6,11 +6,13 @@ import (
 	"crypto/ecdsa"
 	"decipher.com/tps/config"
 	"fmt"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"log"
 	"math/big"
 	"os"
@@ -106,6 +108,9 @@ func UpdateAddress(ERC20, ERC721, ERC1155 common.Address) {
 	if err != nil {
 		log.Printf("failed to write file: %v", err)
 	}
+	config.ERC20ADDRESS = ERC20
+	config.ERC721ADDRESS = ERC721
+	config.ERC1155ADDRESS = ERC1155
 }
 
 func updateAddresses(content string, ERC20, ERC721, ERC1155 common.Address) string {
@@ -209,36 +214,58 @@ func initialize(client *ethclient.Client, privateKey *ecdsa.PrivateKey) (*ethcli
 	return client, chain, Trader
 }
 
-func waitMined(ctx context.Context, client *ethclient.Client, tx *types.Transaction, msg ...string) bool {
+// waitMined polls the receipt of tx. It returns an error if the transaction
+// reverted or was not mined.
+// receiptReader is the client the receipts are polled from, an
+// *ethclient.Client or the pooled submitRouter.
+type receiptReader interface {
+	TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	Client() *rpc.Client
+}
+
+func waitMined(ctx context.Context, client receiptReader, errs contractErrors, tx *types.Transaction, msg ...string) (*types.Receipt, error) {
+	return waitMinedAny(ctx, client, errs, []*types.Transaction{tx}, msg...)
+}
+
+// waitMinedAny polls the receipts of txs, replacements of one another, until
+// the first of them is mined. The revert of a failed one is decoded with errs.
+func waitMinedAny(ctx context.Context, client receiptReader, errs contractErrors, txs []*types.Transaction, msg ...string) (*types.Receipt, error) {
 	queryTicker := time.NewTicker(time.Millisecond * 100)
 	defer queryTicker.Stop()
 
 	count := 0
 	for {
-		receipt, err := client.TransactionReceipt(ctx, tx.Hash())
-		if err == nil {
+		for _, tx := range txs {
+			receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+			if err != nil {
+				continue
+			}
 			if receipt.Status == 0 {
 				log.Println("failed transaction:", msg, receipt)
-				return false
+				return receipt, &RevertError{Hash: tx.Hash(), Reason: revertReason(ctx, client, errs, tx, receipt)}
 			}
-			return true
+			return receipt, nil
 		}
 		select {
 		case <-ctx.Done():
-			log.Println("failed transaction error:", ctx.Err())
-			return false
+			if ctx.Err() != context.DeadlineExceeded {
+				log.Println("failed transaction error:", ctx.Err())
+			}
+			return nil, ctx.Err()
 		case <-queryTicker.C:
 			count++
 			if count >= 600 {
 				var result map[string]string
-				err = client.Client().CallContext(ctx, &result, "txpool_status")
+				err := client.Client().CallContext(ctx, &result, "txpool_status")
 				if err != nil {
-					return false
+					return nil, err
 				}
 				pendingTransaction, _ := strconv.ParseInt(result["pending"], 0, 64)
 				if pendingTransaction == 0 {
-					log.Println("Fail to mine:", count, tx.Hash())
-					return false
+					hash := txs[len(txs)-1].Hash()
+					log.Println("Fail to mine:", count, hash)
+					return nil, fmt.Errorf("transaction %v not mined after %v polls", hash, count)
 				}
 			}
 		}
@@ -0,0 +1,110 @@
+package benchmark
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"decipher.com/tps/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// hotAddress receives the transactions routed to the shared hot state. It is
+// not one of the benchmark accounts, so it never collides with disjoint state.
+var hotAddress = common.BytesToAddress(crypto.Keccak256([]byte("antps.hot")))
+
+// isHot reports whether transaction id touches the hot state. With Contention
+// percent, the hot transactions are spread evenly over the run.
+func (bc *BenchmarkContext) isHot(id int) bool {
+	return id*bc.Contention/100 != (id-1)*bc.Contention/100
+}
+
+// hotTokenID is the ERC1155 token id shared by the hot transfers: the id of
+// the first hot transaction, which no disjoint transaction transfers.
+func (bc *BenchmarkContext) hotTokenID() int64 {
+	if bc.Contention <= 0 {
+		return 0
+	}
+	return int64((100 + bc.Contention - 1) / bc.Contention)
+}
+
+// erc1155Transfer returns the token id and amount transferred by transaction
+// id. A hot transaction moves one unit of the hot token, so the minted supply
+// of that token covers all of them.
+func (bc *BenchmarkContext) erc1155Transfer(id int, amount *big.Int) (int64, *big.Int) {
+	if bc.isHot(id) {
+		return bc.hotTokenID(), big.NewInt(1)
+	}
+	return int64(id), amount
+}
+
+// mintRecipient returns the receiver of the token minted by transaction id. The
+// disjoint mints go to Owner, which holds the tokens the transfer workloads
+// move, the hot mints to the shared hot address.
+func (bc *BenchmarkContext) mintRecipient(id int) common.Address {
+	if bc.isHot(id) {
+		return hotAddress
+	}
+	return bc.Owner
+}
+
+type ContentionPoint struct {
+	Contention   int       `json:"contention"`
+	AvgTPS       Summary   `json:"avg_tps"`
+	SteadyTPS    Summary   `json:"steady_tps"`
+	OperationTPS Summary   `json:"operation_tps"`
+	AvgLatency   Summary   `json:"avg_latency"`
+	Trials       []*Result `json:"trials"`
+}
+
+type ContentionReport struct {
+	Workload string            `json:"workload"`
+	Network  string            `json:"network"`
+	Started  time.Time         `json:"started"`
+	Points   []ContentionPoint `json:"points"`
+}
+
+// SweepContention runs the benchmark at every contention level, opts.Trials
+// times each, and reports the TPS as a function of the contention.
+func SweepContention(levels []int, opts RepeatOptions, run func(contention int) *Result) *ContentionReport {
+	report := &ContentionReport{Network: config.Network, Started: time.Now()}
+	trials := max(opts.Trials, 1)
+	for i, level := range levels {
+		if level < 0 || level > 100 {
+			log.Fatalf("contention %d is not between 0 and 100", level)
+		}
+		point := ContentionPoint{Contention: level}
+		for j := 0; j < trials; j++ {
+			if i > 0 || j > 0 {
+				opts.pause()
+			}
+			log.Printf("===== contention %d%%, trial %d/%d =====\n", level, j+1, trials)
+			point.Trials = append(point.Trials, run(level))
+		}
+
+		metric := func(get func(*Result) float64) Summary {
+			values := make([]float64, 0, len(point.Trials))
+			for _, result := range point.Trials {
+				values = append(values, get(result))
+			}
+			return summarize(values)
+		}
+		point.AvgTPS = metric(func(r *Result) float64 { return r.AvgTPS })
+		point.SteadyTPS = metric(func(r *Result) float64 { return r.SteadyTPS })
+		point.OperationTPS = metric(func(r *Result) float64 { return r.OperationTPS })
+		point.AvgLatency = metric(func(r *Result) float64 { return r.AvgLatency })
+		report.Points = append(report.Points, point)
+	}
+	report.Workload = report.Points[0].Trials[0].Workload
+
+	log.Println("contention  avg_tps  steady_tps  avg_latency")
+	for _, point := range report.Points {
+		log.Printf("%9d%%  %7.2f  %10.2f  %11.2f\n", point.Contention, point.AvgTPS.Mean, point.SteadyTPS.Mean, point.AvgLatency.Mean)
+	}
+
+	filename := fmt.Sprintf("%v.%v.%v.contention.json", config.Network, report.Started.Format("20060102_150405"), report.Workload)
+	StoreReport(report, filename)
+	return report
+}
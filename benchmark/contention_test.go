@@ -0,0 +1,61 @@
+package benchmark
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestIsHot(t *testing.T) {
+	for _, contention := range []int{0, 10, 25, 33, 50, 100} {
+		bc := &BenchmarkContext{Contention: contention}
+		hot := 0
+		for id := 1; id <= 1000; id++ {
+			if bc.isHot(id) {
+				hot++
+			}
+		}
+		if want := 1000 * contention / 100; hot != want {
+			t.Errorf("contention %d: %d hot transactions, want %d", contention, hot, want)
+		}
+	}
+}
+
+func TestERC1155HotTransfer(t *testing.T) {
+	amount := big.NewInt(1000)
+
+	for _, contention := range []int{10, 33, 50, 100} {
+		bc := &BenchmarkContext{Contention: contention}
+		hotID := bc.hotTokenID()
+		if !bc.isHot(int(hotID)) {
+			t.Errorf("contention %d: hot token %d is transferred by a disjoint transaction", contention, hotID)
+		}
+		for id := 1; id <= 200; id++ {
+			tokenID, value := bc.erc1155Transfer(id, amount)
+			if bc.isHot(id) && (tokenID != hotID || value.Int64() != 1) {
+				t.Errorf("contention %d: hot transaction %d transfers %v of %d", contention, id, value, tokenID)
+			}
+			if !bc.isHot(id) && tokenID == hotID {
+				t.Errorf("contention %d: disjoint transaction %d transfers the hot token", contention, id)
+			}
+		}
+	}
+}
+
+func TestMintRecipient(t *testing.T) {
+	owner := common.HexToAddress("0x01")
+
+	bc := &BenchmarkContext{Owner: owner}
+	for id := 1; id <= 4; id++ {
+		if got := bc.mintRecipient(id); got != owner {
+			t.Errorf("disjoint mint %d to %v", id, got)
+		}
+	}
+	bc.Contention = 50
+	for id := 1; id <= 4; id++ {
+		if got := bc.mintRecipient(id); (got == hotAddress) != bc.isHot(id) || (got == owner) == bc.isHot(id) {
+			t.Errorf("mint %d to %v, hot %v", id, got, bc.isHot(id))
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"math/rand"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// CustomOptions describes a workload calling an arbitrary contract method.
+// The contract is deployed from Bin when it is set, otherwise Address is used.
+// Args and ConstructorArgs are templates, see expandArg.
+type CustomOptions struct {
+	ABI             string   `yaml:"abi"`
+	Bin             string   `yaml:"bin"`
+	Address         string   `yaml:"address"`
+	Method          string   `yaml:"method"`
+	Args            []string `yaml:"args"`
+	ConstructorArgs []string `yaml:"constructorArgs"`
+}
+
+func (opts CustomOptions) Validate() error {
+	if opts.ABI == "" || opts.Method == "" {
+		return fmt.Errorf("custom workload requires abi and method")
+	}
+	if opts.Bin == "" && opts.Address == "" {
+		return fmt.Errorf("custom workload requires bin or address")
+	}
+	return nil
+}
+
+// Workload returns the custom workload of opts.
+func (opts CustomOptions) Workload() (Workload, error) {
+	return CustomWorkload(opts)
+}
+
+var placeholderPattern = regexp.MustCompile(`\{(sender|recipient|index|random_uint|account\[(\d+)\])\}`)
+
+// expandArg replaces the placeholders of an argument template with the
+// accounts of bc:
+//
+//	{sender}      address sending the transaction
+//	{recipient}   address of the account with the transaction id
+//	{index}       transaction id
+//	{random_uint} random 64 bit unsigned integer
+//	{account[n]}  address of the n-th account
+func expandArg(bc *BenchmarkContext, template string, sender common.Address, id int) (string, error) {
+	var err error
+	expanded := placeholderPattern.ReplaceAllStringFunc(template, func(match string) string {
+		groups := placeholderPattern.FindStringSubmatch(match)
+		switch {
+		case groups[1] == "sender":
+			return sender.Hex()
+		case groups[1] == "recipient":
+			if id < 1 || id > len(bc.Accounts) {
+				err = fmt.Errorf("no account for recipient of transaction %d", id)
+				return match
+			}
+			return bc.recipient(id).Hex()
+		case groups[1] == "index":
+			return strconv.Itoa(id)
+		case groups[1] == "random_uint":
+			return strconv.FormatUint(rand.Uint64(), 10)
+		default:
+			n, _ := strconv.Atoi(groups[2])
+			if n >= len(bc.Accounts) {
+				err = fmt.Errorf("account[%d] is not loaded", n)
+				return match
+			}
+			return crypto.PubkeyToAddress(bc.Accounts[n].PublicKey).Hex()
+		}
+	})
+	return expanded, err
+}
+
+// customArgs expands and converts the argument templates for the given inputs.
+func customArgs(bc *BenchmarkContext, inputs ethabi.Arguments, templates []string, sender common.Address, id int) ([]interface{}, error) {
+	if len(inputs) != len(templates) {
+		return nil, fmt.Errorf("expected %d arguments, got %d", len(inputs), len(templates))
+	}
+	args := make([]interface{}, len(inputs))
+	for i, input := range inputs {
+		expanded, err := expandArg(bc, templates[i], sender, id)
+		if err != nil {
+			return nil, err
+		}
+		value, err := convertArg(input.Type, expanded)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%v): %v", i, input.Type, err)
+		}
+		args[i] = value.Interface()
+	}
+	return args, nil
+}
+
+// convertArg parses s into the Go value the abi package packs for t.
+func convertArg(t ethabi.Type, s string) (reflect.Value, error) {
+	s = strings.TrimSpace(s)
+	switch t.T {
+	case ethabi.IntTy, ethabi.UintTy:
+		n, ok := new(big.Int).SetString(s, 0)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("invalid integer %q", s)
+		}
+		goType := t.GetType()
+		if goType == reflect.TypeOf(&big.Int{}) {
+			return reflect.ValueOf(n), nil
+		}
+		v := reflect.New(goType).Elem()
+		if t.T == ethabi.UintTy {
+			if n.Sign() < 0 || !n.IsUint64() || v.OverflowUint(n.Uint64()) {
+				return reflect.Value{}, fmt.Errorf("%v out of range", n)
+			}
+			v.SetUint(n.Uint64())
+		} else {
+			if !n.IsInt64() || v.OverflowInt(n.Int64()) {
+				return reflect.Value{}, fmt.Errorf("%v out of range", n)
+			}
+			v.SetInt(n.Int64())
+		}
+		return v, nil
+	case ethabi.BoolTy:
+		b, err := strconv.ParseBool(s)
+		return reflect.ValueOf(b), err
+	case ethabi.StringTy:
+		return reflect.ValueOf(s), nil
+	case ethabi.AddressTy:
+		if !common.IsHexAddress(s) {
+			return reflect.Value{}, fmt.Errorf("invalid address %q", s)
+		}
+		return reflect.ValueOf(common.HexToAddress(s)), nil
+	case ethabi.BytesTy:
+		b, err := hexutil.Decode(s)
+		return reflect.ValueOf(b), err
+	case ethabi.FixedBytesTy:
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(b) > t.Size {
+			return reflect.Value{}, fmt.Errorf("%d bytes do not fit in bytes%d", len(b), t.Size)
+		}
+		v := reflect.New(t.GetType()).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		return v, nil
+	case ethabi.SliceTy, ethabi.ArrayTy:
+		elements := splitList(s)
+		if t.T == ethabi.ArrayTy && len(elements) != t.Size {
+			return reflect.Value{}, fmt.Errorf("expected %d elements, got %d", t.Size, len(elements))
+		}
+		v := reflect.New(t.GetType()).Elem()
+		if t.T == ethabi.SliceTy {
+			v = reflect.MakeSlice(t.GetType(), len(elements), len(elements))
+		}
+		for i, element := range elements {
+			e, err := convertArg(*t.Elem, element)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			v.Index(i).Set(e)
+		}
+		return v, nil
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type %v", t)
+	}
+}
+
+// splitList splits "[a,b,[c,d]]" into its top level elements.
+func splitList(s string) []string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	var elements []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				elements = append(elements, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(elements, s[start:])
+}
+
+// Custom benchmarks opts.Method of the contract described by opts.ABI.
+func Custom(total int, profile LoadProfile, opts CustomOptions) *Result {
+	w, err := CustomWorkload(opts)
+	if err != nil {
+		log.Fatalf("Invalid custom workload: %v", err)
+	}
+	return run(NewRunner(total), profile, w)
+}
+
+// CustomWorkload calls opts.Method of the contract described by opts.ABI,
+// deployed from opts.Bin by the setup when it is set.
+func CustomWorkload(opts CustomOptions) (Workload, error) {
+	content, err := os.ReadFile(opts.ABI)
+	if err != nil {
+		return nil, fmt.Errorf("read ABI: %w", err)
+	}
+	parsed, err := ethabi.JSON(strings.NewReader(string(content)))
+	if err != nil {
+		return nil, fmt.Errorf("parse ABI: %w", err)
+	}
+	method, ok := parsed.Methods[opts.Method]
+	if !ok {
+		return nil, fmt.Errorf("method %q not found in %v", opts.Method, opts.ABI)
+	}
+
+	var contract *bind.BoundContract
+	return &workload{
+		name: "custom_" + method.Name,
+		setup: func(ctx context.Context, bc *BenchmarkContext) error {
+			if _, err := customArgs(bc, method.Inputs, opts.Args, bc.Owner, 1); err != nil {
+				return fmt.Errorf("invalid arguments for %v: %w", opts.Method, err)
+			}
+			bc.Failures.decodeErrors(parsed)
+			address := common.HexToAddress(opts.Address)
+			if opts.Bin != "" {
+				if address, err = deployCustom(ctx, bc, parsed, opts); err != nil {
+					return err
+				}
+			}
+			bc.ContractAddress = address
+			contract = bind.NewBoundContract(address, parsed, bc.Backend, bc.Backend, bc.Backend)
+			return nil
+		},
+		transaction: func(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error) {
+			args, err := customArgs(bc, method.Inputs, opts.Args, tx.Sender, tx.Index)
+			if err != nil {
+				return nil, err
+			}
+			return contract.Transact(tx.Opts, opts.Method, args...)
+		},
+	}, nil
+}
+
+func deployCustom(ctx context.Context, bc *BenchmarkContext, parsed ethabi.ABI, opts CustomOptions) (common.Address, error) {
+	bin, err := os.ReadFile(opts.Bin)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("read bytecode: %w", err)
+	}
+	args, err := customArgs(bc, parsed.Constructor.Inputs, opts.ConstructorArgs, bc.Owner, 0)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid constructor arguments: %w", err)
+	}
+	return bc.Deploy(ctx, "Custom contract", func(chain *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, error) {
+		address, tx, _, err := bind.DeployContract(chain, parsed, common.FromHex(strings.TrimSpace(string(bin))), backend, args...)
+		return address, tx, err
+	})
+}
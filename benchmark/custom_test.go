@@ -0,0 +1,86 @@
+package benchmark
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+	"testing"
+
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const customTestABI = `[{"type":"function","name":"call","inputs":[
+	{"name":"to","type":"address"},
+	{"name":"amount","type":"uint256"},
+	{"name":"small","type":"uint32"},
+	{"name":"flag","type":"bool"},
+	{"name":"ids","type":"uint256[]"},
+	{"name":"pair","type":"uint8[2]"},
+	{"name":"tag","type":"bytes4"},
+	{"name":"data","type":"bytes"}]}]`
+
+func TestCustomArgs(t *testing.T) {
+	parsed, err := ethabi.JSON(strings.NewReader(customTestABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	method := parsed.Methods["call"]
+	sender := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	templates := []string{"{sender}", "{index}000", "7", "true", "[1,{index}]", "[3,4]", "0x01020304", "0xff"}
+	bc := &BenchmarkContext{}
+
+	args, err := customArgs(bc, method.Inputs, templates, sender, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args[0] != sender {
+		t.Errorf("to = %v", args[0])
+	}
+	if args[1].(*big.Int).Int64() != 5000 {
+		t.Errorf("amount = %v", args[1])
+	}
+	if args[2] != uint32(7) || args[3] != true {
+		t.Errorf("small, flag = %v, %v", args[2], args[3])
+	}
+	if ids := args[4].([]*big.Int); len(ids) != 2 || ids[1].Int64() != 5 {
+		t.Errorf("ids = %v", ids)
+	}
+	if args[5] != [2]uint8{3, 4} || args[6] != [4]byte{1, 2, 3, 4} {
+		t.Errorf("pair, tag = %v, %v", args[5], args[6])
+	}
+	if _, err = method.Inputs.Pack(args...); err != nil {
+		t.Errorf("pack: %v", err)
+	}
+
+	bad := append([]string{}, templates...)
+	bad[2] = "4294967296"
+	if _, err = customArgs(bc, method.Inputs, bad, sender, 5); err == nil {
+		t.Error("expected overflow of uint32")
+	}
+	if _, err = customArgs(bc, method.Inputs, templates[:2], sender, 5); err == nil {
+		t.Error("expected argument count mismatch")
+	}
+
+	// the accounts are the ones of the run
+	bad[0], bad[2] = "{recipient}", "7"
+	if _, err = customArgs(bc, method.Inputs, bad, sender, 1); err == nil {
+		t.Error("expected a missing recipient account")
+	}
+	key, _ := crypto.GenerateKey()
+	bc.Accounts = []*ecdsa.PrivateKey{key}
+	if args, err = customArgs(bc, method.Inputs, bad, sender, 1); err != nil || args[0] != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("recipient = %v, %v", args, err)
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	got := splitList("[1, [2,3], 4]")
+	if len(got) != 3 || strings.TrimSpace(got[1]) != "[2,3]" {
+		t.Errorf("splitList = %q", got)
+	}
+	if got = splitList("[]"); len(got) != 0 {
+		t.Errorf("splitList([]) = %q", got)
+	}
+}
@@ -0,0 +1,216 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"decipher.com/tps/abi"
+	"decipher.com/tps/config"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// maxCodeSize is the runtime code size limit of EIP-170.
+const maxCodeSize = 24576
+
+// DeployOptions configures the contract creation benchmark. Bytecode is
+// "erc20", "erc721", "erc1155" or "synthetic", the latter deploys Size bytes of
+// runtime code. Create2 deploys through the CREATE2 factory instead of sending
+// creation transactions. The transactions are spread over Senders accounts.
+type DeployOptions struct {
+	Bytecode string `yaml:"bytecode"`
+	Size     int    `yaml:"size"`
+	Create2  bool   `yaml:"create2"`
+	Senders  int    `yaml:"senders"`
+}
+
+func (opts DeployOptions) Validate() error {
+	_, _, err := opts.initCode()
+	return err
+}
+
+// AccountsFor returns the number of accounts sending the creations.
+func (opts DeployOptions) AccountsFor(total int) int {
+	return max(opts.Senders, config.Multi, 1)
+}
+
+// Workload returns the deploy workload of opts.
+func (opts DeployOptions) Workload() (Workload, error) {
+	return DeployWorkload(opts)
+}
+
+// initCode returns the creation code and the expected runtime size, 0 if unknown.
+func (opts DeployOptions) initCode() ([]byte, int, error) {
+	switch opts.Bytecode {
+	case "erc20":
+		parsed, _ := abi.ERC20MetaData.GetAbi()
+		totalSupply := new(big.Int).Mul(config.OneEther, big.NewInt(1000000000))
+		args, err := parsed.Pack("", totalSupply)
+		if err != nil {
+			return nil, 0, err
+		}
+		return append(common.FromHex(abi.ERC20MetaData.Bin), args...), 0, nil
+	case "erc721":
+		return common.FromHex(abi.ERC721MetaData.Bin), 0, nil
+	case "erc1155":
+		return common.FromHex(abi.ERC1155MetaData.Bin), 0, nil
+	case "synthetic", "":
+		if opts.Size <= 0 || opts.Size > maxCodeSize {
+			return nil, 0, fmt.Errorf("size must be between 1 and %d bytes", maxCodeSize)
+		}
+		return syntheticInitCode(opts.Size), opts.Size, nil
+	default:
+		return nil, 0, fmt.Errorf("unknown bytecode %q", opts.Bytecode)
+	}
+}
+
+func (opts DeployOptions) operationType() string {
+	bytecode := opts.Bytecode
+	if bytecode == "synthetic" || bytecode == "" {
+		bytecode = fmt.Sprintf("synthetic%d", opts.Size)
+	}
+	if opts.Create2 {
+		return "deploy_" + bytecode + "_create2"
+	}
+	return "deploy_" + bytecode + "_create"
+}
+
+// syntheticInitCode returns creation code whose runtime is size JUMPDESTs,
+// using the same constructor as the hand assembled contracts.
+func syntheticInitCode(size int) []byte {
+	code := common.FromHex(fmt.Sprintf("61%04x80600c6000396000f3", size))
+	for i := 0; i < size; i++ {
+		code = append(code, 0x5b)
+	}
+	return code
+}
+
+// DeployBench measures the throughput and latency of contract creation.
+func DeployBench(total int, profile LoadProfile, opts DeployOptions) *Result {
+	w, err := DeployWorkload(opts)
+	if err != nil {
+		log.Fatalf("Invalid deploy options: %v", err)
+	}
+	return run(NewRunner(total), profile, w)
+}
+
+// DeployWorkload creates a contract with every transaction, deploying the
+// CREATE2 factory first with opts.Create2.
+func DeployWorkload(opts DeployOptions) (Workload, error) {
+	code, runtimeSize, err := opts.initCode()
+	if err != nil {
+		return nil, err
+	}
+	if opts.Senders <= 0 {
+		opts.Senders = max(config.Multi, 1)
+	}
+
+	var factory common.Address
+	// salts are unique per run, so CREATE2 never collides with a previous run
+	runID := big.NewInt(time.Now().UnixNano())
+	salt := func(id int) common.Hash {
+		return common.BigToHash(new(big.Int).Add(new(big.Int).Lsh(runID, 64), big.NewInt(int64(id))))
+	}
+	calldata := func(id int) []byte {
+		if opts.Create2 {
+			return append(salt(id).Bytes(), code...)
+		}
+		return code
+	}
+
+	var gas uint64
+	return &workload{
+		name: opts.operationType(),
+		setup: func(ctx context.Context, bc *BenchmarkContext) error {
+			if opts.Senders > len(bc.Accounts) {
+				return fmt.Errorf("%d senders requested, %d accounts loaded", opts.Senders, len(bc.Accounts))
+			}
+			var err error
+			if opts.Create2 {
+				factory, err = bc.Deploy(ctx, "CREATE2 factory", func(chain *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, error) {
+					address, tx, _, err := abi.DeployCreate2Factory(chain, backend)
+					return address, tx, err
+				})
+				if err != nil {
+					return err
+				}
+			}
+			bc.ContractAddress = factory
+			bc.SendFrom(bc.Accounts[:opts.Senders]...)
+			if gas, err = estimateDeployGas(ctx, bc.Client, bc.Owner, factory, opts.Create2, calldata(0)); err != nil {
+				return err
+			}
+			log.Printf("init code %d bytes, runtime %d bytes, gas limit %d\n", len(code), runtimeSize, gas)
+			return nil
+		},
+		transaction: func(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error) {
+			var to *common.Address
+			if opts.Create2 {
+				to = &factory
+			}
+			signedTx, err := tx.Opts.Signer(tx.Sender, types.NewTx(&types.LegacyTx{
+				Nonce:    tx.Nonce,
+				To:       to,
+				Gas:      gas,
+				GasPrice: tx.Opts.GasPrice,
+				Data:     calldata(tx.Index),
+			}))
+			if err != nil {
+				return nil, err
+			}
+			return signedTx, bc.Backend.SendTransaction(ctx, signedTx)
+		},
+		verify: func(ctx context.Context, bc *BenchmarkContext) *Verification {
+			created := make(map[int]common.Address)
+			for id, receipt := range bc.Succeeded() {
+				if opts.Create2 {
+					created[id] = crypto.CreateAddress2(factory, salt(id), crypto.Keccak256(code))
+				} else {
+					created[id] = receipt.ContractAddress
+				}
+			}
+			return verifyCode(bc.Client, created, runtimeSize)
+		},
+	}, nil
+}
+
+// estimateDeployGas estimates one creation and adds a margin, the estimate
+// of the first salt may differ slightly from the following ones.
+func estimateDeployGas(ctx context.Context, client *ethclient.Client, from common.Address, factory common.Address, create2 bool, data []byte) (uint64, error) {
+	msg := ethereum.CallMsg{From: from, Data: data}
+	if create2 {
+		msg.To = &factory
+	}
+	gas, err := client.EstimateGas(ctx, msg)
+	if err != nil {
+		return 0, fmt.Errorf("estimate the deploy gas: %w", err)
+	}
+	return gas * 6 / 5, nil
+}
+
+// verifyCode checks that code exists at the created addresses, of size bytes if known.
+func verifyCode(client *ethclient.Client, created map[int]common.Address, size int) *Verification {
+	v := &Verification{Checked: len(created)}
+	expected := "non-empty"
+	if size > 0 {
+		expected = fmt.Sprintf("%d bytes", size)
+	}
+	for _, address := range created {
+		code, err := client.CodeAt(context.Background(), address, nil)
+		if err != nil {
+			v.Mismatches = append(v.Mismatches, Mismatch{"contract_code", address.Hex(), expected, err.Error()})
+			continue
+		}
+		if len(code) == 0 || (size > 0 && len(code) != size) {
+			v.Mismatches = append(v.Mismatches, Mismatch{"contract_code", address.Hex(), expected, fmt.Sprintf("%d bytes", len(code))})
+		}
+	}
+	return v.log()
+}
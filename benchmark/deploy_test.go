@@ -0,0 +1,35 @@
+package benchmark
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSyntheticInitCode(t *testing.T) {
+	code := syntheticInitCode(300)
+	if len(code) != 12+300 {
+		t.Fatalf("len = %d", len(code))
+	}
+	// PUSH2 0x012c: the constructor returns exactly the appended runtime
+	if !bytes.Equal(code[:3], []byte{0x61, 0x01, 0x2c}) {
+		t.Errorf("constructor = %x", code[:12])
+	}
+}
+
+func TestDeployOptions(t *testing.T) {
+	opts := DeployOptions{Bytecode: "synthetic", Size: 1024, Create2: true}
+	if got := opts.operationType(); got != "deploy_synthetic1024_create2" {
+		t.Errorf("operationType = %v", got)
+	}
+	if _, size, err := opts.initCode(); err != nil || size != 1024 {
+		t.Errorf("initCode = %v, %v", size, err)
+	}
+	for _, bad := range []DeployOptions{{Bytecode: "synthetic", Size: maxCodeSize + 1}, {Bytecode: "erc4626"}} {
+		if _, _, err := bad.initCode(); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+	if code, _, err := (DeployOptions{Bytecode: "erc20"}).initCode(); err != nil || len(code) == 0 {
+		t.Errorf("erc20 initCode: %v", err)
+	}
+}
@@ -0,0 +1,236 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math/big"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"decipher.com/tps/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// SubmitStrategies choose the submit endpoint of a transaction: in turn, by
+// sender, at random or always the first one.
+var SubmitStrategies = []string{"round-robin", "sticky", "random", "all-to-one"}
+
+// EndpointStats are the submission errors and eth_sendRawTransaction
+// latencies (seconds) of one endpoint over its pool of connections.
+type EndpointStats struct {
+	URL         string   `json:"url"`
+	Role        string   `json:"role"`
+	Transport   string   `json:"transport"`
+	Connections int      `json:"connections"`
+	Submitted   int      `json:"submitted"`
+	Errors      int      `json:"errors"`
+	ErrorRate   float64  `json:"error_rate"`
+	AvgLatency  float64  `json:"avg_latency"`
+	LatencyP50  float64  `json:"latency_p50"`
+	LatencyP95  float64  `json:"latency_p95"`
+	LatencyP99  float64  `json:"latency_p99"`
+	Samples     []string `json:"samples,omitempty"`
+}
+
+// submitRouter is the contract backend of the workloads. Transactions are sent
+// to the submit endpoint picked by the strategy over one of its pooled
+// connections. The reads made for every transaction, its receipt, nonce and gas
+// price, go over the pool of the first submit endpoint, every other request to
+// the primary client of the run.
+type submitRouter struct {
+	*primaryClient
+	endpoints []config.Endpoint
+	pools     []*connectionPool
+	recorders []*latencyRecorder
+	strategy  string
+	next      atomic.Uint64
+	signer    types.Signer
+}
+
+// primaryClient embeds the primary client in submitRouter under its own name,
+// so the router has its Client method as well.
+type primaryClient = ethclient.Client
+
+// newSubmitRouter dials connections clients to every submit endpoint of
+// endpoints, strategy picks the one a transaction is sent to.
+func newSubmitRouter(primary *ethclient.Client, endpoints []config.Endpoint, strategy string, connections int) (*submitRouter, error) {
+	r := &submitRouter{primaryClient: primary, strategy: strategy, signer: types.LatestSignerForChainID(config.ChainID)}
+	if err := validateStrategy(r.strategy); err != nil {
+		return nil, err
+	}
+	for _, endpoint := range endpoints {
+		if !endpoint.Submits() {
+			continue
+		}
+		pool, err := dialPool(endpoint, max(connections, 1))
+		if err != nil {
+			r.close()
+			return nil, fmt.Errorf("dial %v: %w", endpoint.URL, err)
+		}
+		r.endpoints = append(r.endpoints, endpoint)
+		r.pools = append(r.pools, pool)
+		r.recorders = append(r.recorders, &latencyRecorder{})
+	}
+	return r, nil
+}
+
+// connectionPool hands out its clients in turn. Each client has its own
+// websocket, IPC or HTTP connections.
+type connectionPool struct {
+	clients []*ethclient.Client
+	next    atomic.Uint64
+}
+
+func dialPool(endpoint config.Endpoint, size int) (*connectionPool, error) {
+	pool := &connectionPool{}
+	for i := 0; i < size; i++ {
+		client, err := dialEndpoint(endpoint)
+		if err != nil {
+			pool.close()
+			return nil, err
+		}
+		pool.clients = append(pool.clients, client)
+	}
+	return pool, nil
+}
+
+// dialEndpoint connects to endpoint. HTTP clients get their own transport, so
+// that a pool is not limited by the idle connections of the default one.
+func dialEndpoint(endpoint config.Endpoint) (*ethclient.Client, error) {
+	if endpoint.Transport != "http" {
+		return ethclient.Dial(endpoint.URL)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 64
+	client, err := rpc.DialOptions(context.Background(), endpoint.URL, rpc.WithHTTPClient(&http.Client{Transport: transport}))
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(client), nil
+}
+
+func (p *connectionPool) get() *ethclient.Client {
+	return p.clients[(p.next.Add(1)-1)%uint64(len(p.clients))]
+}
+
+func (p *connectionPool) close() {
+	for _, client := range p.clients {
+		client.Close()
+	}
+}
+
+func validateStrategy(strategy string) error {
+	for _, s := range SubmitStrategies {
+		if s == strategy {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown submit strategy %q, expected one of %v", strategy, SubmitStrategies)
+}
+
+// pick returns the index of the endpoint tx is submitted to.
+func (r *submitRouter) pick(tx *types.Transaction) int {
+	n := len(r.pools)
+	switch r.strategy {
+	case "round-robin":
+		return int((r.next.Add(1) - 1) % uint64(n))
+	case "sticky":
+		from, err := types.Sender(r.signer, tx)
+		if err != nil {
+			return 0
+		}
+		h := fnv.New32a()
+		h.Write(from.Bytes())
+		return int(h.Sum32() % uint32(n))
+	case "random":
+		return rand.Intn(n)
+	default:
+		return 0
+	}
+}
+
+func (r *submitRouter) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	i := r.pick(tx)
+	start := time.Now()
+	err := r.pools[i].get().SendTransaction(ctx, tx)
+	if ClassifyError(err) == ErrAlreadyKnown {
+		// the pool holds this very transaction, e.g. after a resend or from another endpoint
+		err = nil
+	}
+	r.recorders[i].record(time.Since(start), err)
+	return err
+}
+
+// reader returns a pooled connection for the reads made per transaction, so
+// they do not queue behind each other on the primary connection.
+func (r *submitRouter) reader() *ethclient.Client {
+	if len(r.pools) == 0 {
+		return r.primaryClient
+	}
+	return r.pools[0].get()
+}
+
+func (r *submitRouter) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	return r.reader().TransactionReceipt(ctx, hash)
+}
+
+func (r *submitRouter) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return r.reader().PendingNonceAt(ctx, account)
+}
+
+func (r *submitRouter) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return r.reader().SuggestGasPrice(ctx)
+}
+
+// stats returns the submission statistics of every submit endpoint.
+func (r *submitRouter) stats() []EndpointStats {
+	stats := make([]EndpointStats, len(r.endpoints))
+	for i, endpoint := range r.endpoints {
+		s := r.recorders[i].stats(endpoint.URL, 0)
+		stats[i] = EndpointStats{
+			URL:         endpoint.URL,
+			Role:        endpoint.Role,
+			Transport:   endpoint.Transport,
+			Connections: len(r.pools[i].clients),
+			Submitted:   s.Requests,
+			Errors:      s.Errors,
+			ErrorRate:   s.ErrorRate,
+			AvgLatency:  s.AvgLatency,
+			LatencyP50:  s.LatencyP50,
+			LatencyP95:  s.LatencyP95,
+			LatencyP99:  s.LatencyP99,
+			Samples:     s.Samples,
+		}
+		if len(r.endpoints) > 1 {
+			log.Printf("endpoint %v: %v submitted, %.2f%% errors, p95 %.4fs\n", endpoint.URL, s.Requests, 100*s.ErrorRate, s.LatencyP95)
+		}
+	}
+	return stats
+}
+
+// transports returns the distinct transports of the submit endpoints.
+func (r *submitRouter) transports() string {
+	var transports []string
+	for _, endpoint := range r.endpoints {
+		if !slices.Contains(transports, endpoint.Transport) {
+			transports = append(transports, endpoint.Transport)
+		}
+	}
+	return strings.Join(transports, ",")
+}
+
+// close closes the pooled connections. The primary client is left open.
+func (r *submitRouter) close() {
+	for _, pool := range r.pools {
+		pool.close()
+	}
+}
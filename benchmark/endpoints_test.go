@@ -0,0 +1,165 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"decipher.com/tps/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func testRouter(strategy string, n int) *submitRouter {
+	r := &submitRouter{strategy: strategy, signer: types.LatestSignerForChainID(big.NewInt(1))}
+	for i := 0; i < n; i++ {
+		r.endpoints = append(r.endpoints, config.Endpoint{URL: "ws://node", Role: "submit", Transport: "ws"})
+		r.pools = append(r.pools, &connectionPool{clients: []*ethclient.Client{nil}})
+		r.recorders = append(r.recorders, &latencyRecorder{})
+	}
+	return r
+}
+
+func signedTx(t *testing.T, nonce uint64) *types.Transaction {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := types.SignTx(types.NewTx(&types.LegacyTx{Nonce: nonce, GasPrice: big.NewInt(1), Gas: 21000}), types.NewEIP155Signer(big.NewInt(1)), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestSubmitStrategies(t *testing.T) {
+	tx := signedTx(t, 0)
+
+	r := testRouter("round-robin", 3)
+	for i := 0; i < 6; i++ {
+		if got := r.pick(tx); got != i%3 {
+			t.Errorf("round-robin pick %d = %d", i, got)
+		}
+	}
+
+	r = testRouter("sticky", 4)
+	first := r.pick(tx)
+	for i := 0; i < 10; i++ {
+		if got := r.pick(tx); got != first {
+			t.Errorf("sticky pick = %d, want %d", got, first)
+		}
+	}
+
+	r = testRouter("all-to-one", 4)
+	if got := r.pick(tx); got != 0 {
+		t.Errorf("all-to-one pick = %d", got)
+	}
+
+	r = testRouter("random", 4)
+	for i := 0; i < 20; i++ {
+		if got := r.pick(tx); got < 0 || got >= 4 {
+			t.Errorf("random pick = %d", got)
+		}
+	}
+
+	if err := validateStrategy("least-loaded"); err == nil {
+		t.Error("expected unknown strategy")
+	}
+}
+
+func TestEndpointStats(t *testing.T) {
+	r := testRouter("round-robin", 2)
+	r.recorders[0].record(10*time.Millisecond, nil)
+	r.recorders[0].record(30*time.Millisecond, nil)
+	r.recorders[1].record(0, errors.New("nonce too low"))
+	stats := r.stats()
+	if len(stats) != 2 {
+		t.Fatalf("stats = %v", stats)
+	}
+	if stats[0].Submitted != 2 || stats[0].Errors != 0 || stats[0].AvgLatency != 0.02 {
+		t.Errorf("endpoint 0 = %+v", stats[0])
+	}
+	if stats[1].Submitted != 1 || stats[1].ErrorRate != 1 || stats[1].Transport != "ws" || stats[1].Connections != 1 {
+		t.Errorf("endpoint 1 = %+v", stats[1])
+	}
+
+	r.endpoints[1].Transport = "http"
+	if got := r.transports(); got != "ws,http" {
+		t.Errorf("transports = %q", got)
+	}
+}
+
+func TestConnectionPool(t *testing.T) {
+	clients := []*ethclient.Client{new(ethclient.Client), new(ethclient.Client), new(ethclient.Client)}
+	pool := &connectionPool{clients: clients}
+	for i := 0; i < 6; i++ {
+		if got := pool.get(); got != clients[i%3] {
+			t.Errorf("get %d returned the wrong client", i)
+		}
+	}
+}
+
+// countingNode answers the per-transaction reads and counts them.
+type countingNode struct {
+	calls atomic.Int64
+}
+
+func (n *countingNode) GetTransactionReceipt(hash common.Hash) (map[string]interface{}, error) {
+	n.calls.Add(1)
+	return nil, nil
+}
+
+func (n *countingNode) GetTransactionCount(account common.Address, block string) (hexutil.Uint64, error) {
+	n.calls.Add(1)
+	return 7, nil
+}
+
+func (n *countingNode) GasPrice() (*hexutil.Big, error) {
+	n.calls.Add(1)
+	return (*hexutil.Big)(big.NewInt(10)), nil
+}
+
+func countingClient(t *testing.T, node *countingNode) *ethclient.Client {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", node); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestRouterPooledReads(t *testing.T) {
+	primary, pooled := new(countingNode), new(countingNode)
+	r := testRouter("round-robin", 1)
+	r.primaryClient = countingClient(t, primary)
+	r.pools[0].clients = []*ethclient.Client{countingClient(t, pooled)}
+
+	key, _ := crypto.GenerateKey()
+	s := newSender(r, key)
+	tx, err := s.tx(context.Background(), 1)
+	if err != nil || tx.Nonce != 7 || tx.Opts.GasPrice.Int64() != 10 {
+		t.Fatalf("tx = %+v, %v", tx, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+	if _, err := waitMined(ctx, r, bundledErrors, signedTx(t, 0)); err != context.DeadlineExceeded {
+		t.Errorf("waitMined = %v", err)
+	}
+
+	// initialize and the first tx read the nonce and gas price twice, the receipt is polled at least twice
+	if got := pooled.calls.Load(); got < 6 {
+		t.Errorf("%v reads on the pooled connection", got)
+	}
+	if got := primary.calls.Load(); got != 0 {
+		t.Errorf("%v reads on the primary connection", got)
+	}
+}
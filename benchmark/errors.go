@@ -0,0 +1,203 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+type ErrorCategory string
+
+const (
+	ErrAlreadyKnown      ErrorCategory = "already_known"
+	ErrNonceTooLow       ErrorCategory = "nonce_too_low"
+	ErrNonceTooHigh      ErrorCategory = "nonce_too_high"
+	ErrUnderpriced       ErrorCategory = "underpriced"
+	ErrTxPoolFull        ErrorCategory = "txpool_full"
+	ErrInsufficientFunds ErrorCategory = "insufficient_funds"
+	ErrGasLimit          ErrorCategory = "gas_limit_exceeded"
+	ErrReverted          ErrorCategory = "reverted"
+	ErrTimeout           ErrorCategory = "timeout"
+	ErrConnectionLost    ErrorCategory = "connection_lost"
+	ErrUnknown           ErrorCategory = "unknown"
+)
+
+// RetryPolicy tells the sender whether a failed submission is sent again.
+// The nonce is always refreshed before a retry.
+type RetryPolicy struct {
+	Retry       bool
+	Backoff     time.Duration
+	MaxAttempts int
+}
+
+var retryPolicies = map[ErrorCategory]RetryPolicy{
+	ErrAlreadyKnown:      {},
+	ErrNonceTooLow:       {Retry: true, Backoff: time.Second, MaxAttempts: 20},
+	ErrNonceTooHigh:      {Retry: true, Backoff: time.Second, MaxAttempts: 20},
+	ErrUnderpriced:       {Retry: true, Backoff: time.Second, MaxAttempts: 20},
+	ErrTxPoolFull:        {Retry: true, Backoff: 2 * time.Second, MaxAttempts: 30},
+	ErrInsufficientFunds: {},
+	ErrGasLimit:          {},
+	ErrReverted:          {},
+	ErrTimeout:           {Retry: true, Backoff: time.Second, MaxAttempts: 3},
+	ErrConnectionLost:    {Retry: true, Backoff: 2 * time.Second, MaxAttempts: 5},
+	ErrUnknown:           {},
+}
+
+// errorPatterns holds the error wording of geth, coreth and klaytn. The first
+// match wins. An already known transaction is held by the pool with the same
+// hash, so the submitter counts it as submitted instead of sending it again.
+var errorPatterns = []struct {
+	category ErrorCategory
+	patterns []string
+}{
+	{ErrAlreadyKnown, []string{"already known", "known transaction:"}},
+	{ErrNonceTooLow, []string{"nonce too low", "there is another tx which has the same nonce"}},
+	{ErrNonceTooHigh, []string{"nonce too high", "nonce gap"}},
+	{ErrUnderpriced, []string{"underpriced", "fee cap less than block base fee", "max fee per gas less than block base fee", "invalid unit price", "gas price lower than"}},
+	{ErrTxPoolFull, []string{"txpool is full", "pool is full", "exceeds the maximum number of transactions", "exceeds the txpool"}},
+	{ErrInsufficientFunds, []string{"insufficient funds", "insufficient balance"}},
+	{ErrGasLimit, []string{"exceeds block gas limit", "gas limit reached", "intrinsic gas too low", "out of gas", "gas limit exceeded", "gas uint64 overflow"}},
+	{ErrReverted, []string{"execution reverted", "reverted", "evm: execution"}},
+	{ErrTimeout, []string{"timeout", "deadline exceeded", "timed out", "not mined"}},
+	{ErrConnectionLost, []string{"connection refused", "connection reset", "broken pipe", "use of closed network connection", "websocket: close", "unexpected eof", "client is closed"}},
+}
+
+// ClassifyError maps a JSON-RPC or receipt error to its category.
+func ClassifyError(err error) ErrorCategory {
+	if err == nil {
+		return ""
+	}
+	var revertErr *RevertError
+	if errors.As(err, &revertErr) {
+		return ErrReverted
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrTimeout
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrConnectionLost
+	}
+	msg := strings.ToLower(err.Error())
+	// a bare EOF is the end of a closed connection, e.g. "read tcp ...: EOF"
+	if msg == "eof" || strings.HasSuffix(msg, ": eof") {
+		return ErrConnectionLost
+	}
+	for _, entry := range errorPatterns {
+		for _, pattern := range entry.patterns {
+			if strings.Contains(msg, pattern) {
+				return entry.category
+			}
+		}
+	}
+	if errors.As(err, &netErr) {
+		return ErrConnectionLost
+	}
+	return ErrUnknown
+}
+
+const maxFailureSamples = 3
+
+// FailureSummary counts the final failures and the retried submissions of one
+// category. Reasons counts the decoded revert reasons of failed receipts.
+type FailureSummary struct {
+	Count   int            `json:"count"`
+	Retries int            `json:"retries"`
+	Samples []string       `json:"samples"`
+	Reasons map[string]int `json:"reasons,omitempty"`
+}
+
+type failureRecorder struct {
+	mutex      sync.Mutex
+	count      int
+	categories map[ErrorCategory]*FailureSummary
+	// errors decode the reverts of the run
+	errors contractErrors
+}
+
+func newFailureRecorder() *failureRecorder {
+	return &failureRecorder{categories: make(map[ErrorCategory]*FailureSummary), errors: bundledErrors}
+}
+
+// decodeErrors makes the run decode the custom errors of parsed as well. It is
+// called by a workload's setup, before any transaction is sent.
+func (f *failureRecorder) decodeErrors(parsed ethabi.ABI) {
+	f.errors = f.errors.with(parsed)
+}
+
+func (f *failureRecorder) summary(category ErrorCategory) *FailureSummary {
+	s, ok := f.categories[category]
+	if !ok {
+		s = &FailureSummary{}
+		f.categories[category] = s
+	}
+	return s
+}
+
+func (f *failureRecorder) addSample(s *FailureSummary, err error) {
+	if len(s.Samples) < maxFailureSamples && !slices.Contains(s.Samples, err.Error()) {
+		s.Samples = append(s.Samples, err.Error())
+	}
+}
+
+// retry returns the policy for err and records the attempt if it may be retried.
+func (f *failureRecorder) retry(err error, attempts int) (RetryPolicy, bool) {
+	category := ClassifyError(err)
+	policy := retryPolicies[category]
+	if !policy.Retry || attempts >= policy.MaxAttempts {
+		return policy, false
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	s := f.summary(category)
+	s.Retries++
+	f.addSample(s, err)
+	return policy, true
+}
+
+func (f *failureRecorder) record(err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.count++
+	s := f.summary(ClassifyError(err))
+	s.Count++
+	f.addSample(s, err)
+
+	var revertErr *RevertError
+	if errors.As(err, &revertErr) {
+		if s.Reasons == nil {
+			s.Reasons = make(map[string]int)
+		}
+		s.Reasons[revertErr.Reason]++
+	}
+}
+
+func (f *failureRecorder) Count() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.count
+}
+
+func (f *failureRecorder) breakdown() map[ErrorCategory]*FailureSummary {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for category, s := range f.categories {
+		log.Printf("%v: %v failed, %v retried %q\n", category, s.Count, s.Retries, s.Samples)
+		for reason, count := range s.Reasons {
+			log.Printf("  %v: %v\n", reason, count)
+		}
+	}
+	return f.categories
+}
@@ -0,0 +1,132 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"decipher.com/tps/abi"
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := map[string]ErrorCategory{
+		"nonce too low: address 0x12, tx: 3 state: 5": ErrNonceTooLow,
+		"already known":           ErrAlreadyKnown,
+		"known transaction: 8fb0": ErrAlreadyKnown,
+		"there is another tx which has the same nonce in the tx pool": ErrNonceTooLow,
+		"nonce too high":                                                          ErrNonceTooHigh,
+		"replacement transaction underpriced":                                     ErrUnderpriced,
+		"transaction underpriced: tip needed 1, tip permitted 0":                  ErrUnderpriced,
+		"invalid unit price":                                                      ErrUnderpriced,
+		"txpool is full":                                                          ErrTxPoolFull,
+		"insufficient funds for gas * price + value":                              ErrInsufficientFunds,
+		"insufficient balance of the sender to pay for gas":                       ErrInsufficientFunds,
+		"exceeds block gas limit":                                                 ErrGasLimit,
+		"intrinsic gas too low":                                                   ErrGasLimit,
+		"execution reverted: ERC721: invalid token ID":                            ErrReverted,
+		"evm: execution reverted":                                                 ErrReverted,
+		"transaction 0xab not mined after 600 polls":                              ErrTimeout,
+		"write tcp 127.0.0.1:1->127.0.0.1:9551: use of closed network connection": ErrConnectionLost,
+		"websocket: close 1006 (abnormal closure): unexpected EOF":                ErrConnectionLost,
+		"read tcp 127.0.0.1:1->127.0.0.1:9551: EOF":                               ErrConnectionLost,
+		"EOF": ErrConnectionLost,
+		"invalid argument 0: hex string of odd length, geoffset": ErrUnknown,
+		"unknown transaction type":                               ErrUnknown,
+		"something else":                                         ErrUnknown,
+	}
+	for msg, want := range cases {
+		if got := ClassifyError(errors.New(msg)); got != want {
+			t.Errorf("ClassifyError(%q) = %v, want %v", msg, got, want)
+		}
+	}
+	if got := ClassifyError(fmt.Errorf("send: %w", context.DeadlineExceeded)); got != ErrTimeout {
+		t.Errorf("deadline exceeded = %v, want %v", got, ErrTimeout)
+	}
+}
+
+func TestFailureRecorder(t *testing.T) {
+	f := newFailureRecorder()
+	underpriced := errors.New("replacement transaction underpriced")
+	if _, ok := f.retry(underpriced, 1); !ok {
+		t.Fatal("underpriced should be retried")
+	}
+	if _, ok := f.retry(underpriced, retryPolicies[ErrUnderpriced].MaxAttempts); ok {
+		t.Fatal("retry should stop at max attempts")
+	}
+	if _, ok := f.retry(errors.New("insufficient funds"), 1); ok {
+		t.Fatal("insufficient funds should not be retried")
+	}
+	for i := 0; i < 5; i++ {
+		f.record(fmt.Errorf("execution reverted: %d", i))
+	}
+
+	if f.Count() != 5 {
+		t.Fatalf("count = %v, want 5", f.Count())
+	}
+	breakdown := f.breakdown()
+	if s := breakdown[ErrReverted]; s.Count != 5 || len(s.Samples) != maxFailureSamples {
+		t.Fatalf("unexpected reverted summary %+v", s)
+	}
+	if s := breakdown[ErrUnderpriced]; s.Count != 0 || s.Retries != 1 {
+		t.Fatalf("unexpected underpriced summary %+v", s)
+	}
+}
+
+func TestDecodeRevert(t *testing.T) {
+	stringType, _ := ethabi.NewType("string", "", nil)
+	packed, _ := ethabi.Arguments{{Type: stringType}}.Pack("ERC721: invalid token ID")
+	data := append(common.FromHex("0x08c379a0"), packed...)
+	if got := bundledErrors.decode(data); got != "ERC721: invalid token ID" {
+		t.Errorf("Error(string) = %q", got)
+	}
+
+	uintType, _ := ethabi.NewType("uint256", "", nil)
+	packed, _ = ethabi.Arguments{{Type: uintType}}.Pack(big.NewInt(0x11))
+	data = append(common.FromHex("0x4e487b71"), packed...)
+	if got := bundledErrors.decode(data); !strings.Contains(got, "overflow") {
+		t.Errorf("Panic(0x11) = %q", got)
+	}
+
+	parsed, err := ethabi.JSON(strings.NewReader(`[{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom := parsed.Errors["InsufficientBalance"]
+	packed, _ = custom.Inputs.Pack(big.NewInt(1), big.NewInt(2))
+	data = append(custom.ID[:4], packed...)
+	run := newFailureRecorder()
+	run.decodeErrors(parsed)
+	if got := run.errors.decode(data); got != "InsufficientBalance(1, 2)" {
+		t.Errorf("custom error = %q", got)
+	}
+	// another run and the bundled errors do not know the custom error
+	if got := newFailureRecorder().errors.decode(data); got != hexutil.Encode(data) {
+		t.Errorf("custom error of another run = %q", got)
+	}
+
+	erc721, err := abi.ERC721MetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonexistent := erc721.Errors["ERC721NonexistentToken"]
+	packed, _ = nonexistent.Inputs.Pack(big.NewInt(7))
+	data = append(nonexistent.ID[:4], packed...)
+	if got := bundledErrors.decode(data); got != "ERC721NonexistentToken(7)" {
+		t.Errorf("bundled error = %q", got)
+	}
+
+	f := newFailureRecorder()
+	f.record(&RevertError{Reason: "ERC721: invalid token ID"})
+	f.record(&RevertError{Reason: "ERC721: invalid token ID"})
+	f.record(&RevertError{Reason: "out of gas"})
+	reasons := f.breakdown()[ErrReverted].Reasons
+	if reasons["ERC721: invalid token ID"] != 2 || reasons["out of gas"] != 1 {
+		t.Errorf("unexpected reasons %v", reasons)
+	}
+}
@@ -0,0 +1,132 @@
+package benchmark
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"decipher.com/tps/abi"
+	"decipher.com/tps/config"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// WatchTarget describes the workload whose operations the block watcher counts
+// when the count mode of the run is "events". Sent counts the submitted transactions
+// for the offered load per block, it may be nil.
+type WatchTarget struct {
+	OperationType string
+	Contract      common.Address
+	Senders       []common.Address
+	Sent          *atomic.Int64
+}
+
+// operationCounter returns the number of successful workload-owned operations in a block.
+type operationCounter func(ctx context.Context, block *types.Block) (int, error)
+
+// newOperationCounter counts the Transfer, TransferSingle and TransferBatch
+// events of the target contract that were emitted by transactions of the
+// workload senders. Native transfers have no events, so their receipts are checked.
+func newOperationCounter(client *ethclient.Client, target WatchTarget) operationCounter {
+	senders := make(map[common.Address]bool)
+	for _, sender := range target.Senders {
+		senders[sender] = true
+	}
+	owned := func(block *types.Block) map[common.Hash]bool {
+		signer := types.LatestSignerForChainID(config.ChainID)
+		hashes := make(map[common.Hash]bool)
+		for _, tx := range block.Transactions() {
+			if from, err := types.Sender(signer, tx); err == nil && senders[from] {
+				hashes[tx.Hash()] = true
+			}
+		}
+		return hashes
+	}
+	opts := func(ctx context.Context, block *types.Block) *bind.FilterOpts {
+		number := block.NumberU64()
+		return &bind.FilterOpts{Start: number, End: &number, Context: ctx}
+	}
+
+	switch {
+	case strings.HasSuffix(target.OperationType, "erc20"):
+		filterer, _ := abi.NewERC20Filterer(target.Contract, client)
+		return func(ctx context.Context, block *types.Block) (int, error) {
+			hashes := owned(block)
+			it, err := filterer.FilterTransfer(opts(ctx, block), nil, nil)
+			if err != nil {
+				return 0, err
+			}
+			defer it.Close()
+			count := 0
+			for it.Next() {
+				if hashes[it.Event.Raw.TxHash] {
+					count++
+				}
+			}
+			return count, it.Error()
+		}
+	case strings.HasSuffix(target.OperationType, "erc721"):
+		filterer, _ := abi.NewERC721Filterer(target.Contract, client)
+		return func(ctx context.Context, block *types.Block) (int, error) {
+			hashes := owned(block)
+			it, err := filterer.FilterTransfer(opts(ctx, block), nil, nil, nil)
+			if err != nil {
+				return 0, err
+			}
+			defer it.Close()
+			count := 0
+			for it.Next() {
+				if hashes[it.Event.Raw.TxHash] {
+					count++
+				}
+			}
+			return count, it.Error()
+		}
+	case strings.HasSuffix(target.OperationType, "erc1155"):
+		filterer, _ := abi.NewERC1155Filterer(target.Contract, client)
+		return func(ctx context.Context, block *types.Block) (int, error) {
+			hashes := owned(block)
+			single, err := filterer.FilterTransferSingle(opts(ctx, block), nil, nil, nil)
+			if err != nil {
+				return 0, err
+			}
+			defer single.Close()
+			count := 0
+			for single.Next() {
+				if hashes[single.Event.Raw.TxHash] {
+					count++
+				}
+			}
+			if err = single.Error(); err != nil {
+				return 0, err
+			}
+			batch, err := filterer.FilterTransferBatch(opts(ctx, block), nil, nil, nil)
+			if err != nil {
+				return 0, err
+			}
+			defer batch.Close()
+			for batch.Next() {
+				if hashes[batch.Event.Raw.TxHash] {
+					count++
+				}
+			}
+			return count, batch.Error()
+		}
+	default:
+		return func(ctx context.Context, block *types.Block) (int, error) {
+			count := 0
+			for hash := range owned(block) {
+				receipt, err := client.TransactionReceipt(ctx, hash)
+				if err != nil {
+					return 0, err
+				}
+				if receipt.Status == types.ReceiptStatusSuccessful {
+					count++
+				}
+			}
+			return count, nil
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package benchmark
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"decipher.com/tps/abi"
+	"decipher.com/tps/config"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func signTestTx(t *testing.T, key *ecdsa.PrivateKey, nonce uint64, to common.Address, data []byte) *types.Transaction {
+	t.Helper()
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(config.ChainID), &types.LegacyTx{
+		Nonce: nonce, To: &to, Gas: 100000, GasPrice: big.NewInt(2 * params.GWei), Data: data,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestOperationCounterIgnoresForeignSenders(t *testing.T) {
+	keys := testKeys(t, 2)
+	workload, foreign := keys[0], keys[1]
+	node := newTestNode(t, keys...)
+	client, err := ethclient.Dial(node.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	auth, _ := bind.NewKeyedTransactorWithChainID(workload, config.ChainID)
+	auth.GasPrice = big.NewInt(2 * params.GWei)
+	token, deployTx, _, err := abi.DeployERC20(auth, client, big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = waitMined(ctx, client, bundledErrors, deployTx); err != nil {
+		t.Fatal(err)
+	}
+	parsed, _ := abi.ERC20MetaData.GetAbi()
+	mint, _ := parsed.Pack("mint", common.Address{1}, big.NewInt(1))
+	sender := crypto.PubkeyToAddress(workload.PublicKey)
+
+	// the transactions of each block go in together with foreign ones
+	count := func(target WatchTarget, txs ...*types.Transaction) int {
+		node.submit(txs...)
+		var block common.Hash
+		for _, tx := range txs {
+			receipt, err := waitMined(ctx, client, bundledErrors, tx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if block != (common.Hash{}) && receipt.BlockHash != block {
+				t.Fatalf("transactions mined in blocks %x and %x", block, receipt.BlockHash)
+			}
+			block = receipt.BlockHash
+		}
+		b, err := client.BlockByHash(ctx, block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, err := newOperationCounter(client, target)(ctx, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	erc20 := WatchTarget{OperationType: "mint_erc20", Contract: token, Senders: []common.Address{sender}}
+	if n := count(erc20, signTestTx(t, workload, 1, token, mint), signTestTx(t, foreign, 0, token, mint), signTestTx(t, foreign, 1, common.Address{1}, nil)); n != 1 {
+		t.Errorf("erc20 operations = %d, want 1 of the workload sender", n)
+	}
+	native := WatchTarget{OperationType: "transfer_native", Senders: []common.Address{sender}}
+	if n := count(native, signTestTx(t, workload, 2, common.Address{1}, nil), signTestTx(t, foreign, 2, common.Address{1}, nil)); n != 1 {
+		t.Errorf("native operations = %d, want 1 of the workload sender", n)
+	}
+}
@@ -0,0 +1,88 @@
+package benchmark
+
+import (
+	"log"
+	"math/big"
+	"slices"
+
+	"decipher.com/tps/config"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// FeeSummary accounts the gas and fees paid by the workload's mined
+// transactions, reverted ones included. Gas prices are in gwei, fees in native
+// units (ETH, AVAX, KLAY). CostPerOperation divides the fees by the successful
+// transactions.
+type FeeSummary struct {
+	TotalGas         uint64          `json:"total_gas"`
+	TotalFees        float64         `json:"total_fees"`
+	AvgGasPrice      float64         `json:"avg_gas_price"`
+	MaxGasPrice      float64         `json:"max_gas_price"`
+	CostPerOperation float64         `json:"cost_per_operation"`
+	GasPrices        []GasPricePoint `json:"gas_prices,omitempty"`
+}
+
+// GasPricePoint is the effective gas price paid by the workload in one block.
+type GasPricePoint struct {
+	Block        uint64  `json:"block"`
+	Transactions int     `json:"transactions"`
+	AvgGasPrice  float64 `json:"avg_gas_price"`
+	MaxGasPrice  float64 `json:"max_gas_price"`
+}
+
+func summarizeFees(receipts map[int]*types.Receipt) *FeeSummary {
+	summary := &FeeSummary{}
+	if len(receipts) == 0 {
+		return summary
+	}
+	fees := new(big.Int)
+	perBlock := make(map[uint64]*GasPricePoint)
+	succeeded := 0
+	for _, receipt := range receipts {
+		summary.TotalGas += receipt.GasUsed
+		fees.Add(fees, fee(receipt))
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			succeeded++
+		}
+		if receipt.EffectiveGasPrice == nil || receipt.BlockNumber == nil {
+			continue
+		}
+		price := gwei(receipt.EffectiveGasPrice)
+		summary.MaxGasPrice = max(summary.MaxGasPrice, price)
+		point, ok := perBlock[receipt.BlockNumber.Uint64()]
+		if !ok {
+			point = &GasPricePoint{Block: receipt.BlockNumber.Uint64()}
+			perBlock[point.Block] = point
+		}
+		// AvgGasPrice holds the sum until all receipts are added
+		point.Transactions++
+		point.AvgGasPrice += price
+		point.MaxGasPrice = max(point.MaxGasPrice, price)
+	}
+
+	summary.TotalFees, _ = new(big.Float).Quo(new(big.Float).SetInt(fees), big.NewFloat(params.Ether)).Float64()
+	if summary.TotalGas > 0 {
+		summary.AvgGasPrice = gwei(new(big.Int).Div(fees, new(big.Int).SetUint64(summary.TotalGas)))
+	}
+	if succeeded > 0 {
+		summary.CostPerOperation = summary.TotalFees / float64(succeeded)
+	}
+	for _, point := range perBlock {
+		point.AvgGasPrice /= float64(point.Transactions)
+		summary.GasPrices = append(summary.GasPrices, *point)
+	}
+	slices.SortFunc(summary.GasPrices, func(a, b GasPricePoint) int { return int(a.Block) - int(b.Block) })
+	return summary
+}
+
+func gwei(wei *big.Int) float64 {
+	value, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(params.GWei)).Float64()
+	return value
+}
+
+func (f *FeeSummary) log() *FeeSummary {
+	log.Printf("total gas = %v, total fees = %v on %v\n", f.TotalGas, f.TotalFees, config.Network)
+	log.Printf("gas price = %.3f gwei avg, %.3f gwei max, cost per operation = %v\n", f.AvgGasPrice, f.MaxGasPrice, f.CostPerOperation)
+	return f
+}
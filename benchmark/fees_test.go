@@ -0,0 +1,43 @@
+package benchmark
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestSummarizeFees(t *testing.T) {
+	receipt := func(block int64, gasUsed uint64, gwei int64, status uint64) *types.Receipt {
+		return &types.Receipt{
+			Status:            status,
+			GasUsed:           gasUsed,
+			EffectiveGasPrice: big.NewInt(gwei * params.GWei),
+			BlockNumber:       big.NewInt(block),
+		}
+	}
+	receipts := map[int]*types.Receipt{
+		1: receipt(10, 50000, 10, types.ReceiptStatusSuccessful),
+		2: receipt(10, 50000, 30, types.ReceiptStatusSuccessful),
+		3: receipt(11, 100000, 20, types.ReceiptStatusFailed),
+	}
+	f := summarizeFees(receipts)
+	if f.TotalGas != 200000 {
+		t.Errorf("total gas = %v", f.TotalGas)
+	}
+	// 50000*10 + 50000*30 + 100000*20 gwei
+	if f.TotalFees != 0.004 {
+		t.Errorf("total fees = %v", f.TotalFees)
+	}
+	if f.AvgGasPrice != 20 || f.MaxGasPrice != 30 {
+		t.Errorf("gas price = %v avg, %v max", f.AvgGasPrice, f.MaxGasPrice)
+	}
+	if f.CostPerOperation != 0.002 {
+		t.Errorf("cost per operation = %v", f.CostPerOperation)
+	}
+	want := []GasPricePoint{{Block: 10, Transactions: 2, AvgGasPrice: 20, MaxGasPrice: 30}, {Block: 11, Transactions: 1, AvgGasPrice: 20, MaxGasPrice: 20}}
+	if len(f.GasPrices) != 2 || f.GasPrices[0] != want[0] || f.GasPrices[1] != want[1] {
+		t.Errorf("gas prices = %+v", f.GasPrices)
+	}
+}
@@ -0,0 +1,94 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// headPollInterval is the polling interval of endpoints without subscriptions.
+	headPollInterval = 500 * time.Millisecond
+	// maxRedials is the number of attempts to reconnect the block watcher.
+	maxRedials    = 30
+	redialBackoff = 2 * time.Second
+)
+
+// watchHeads dials url and sends its new heads to headers, retrying the dial
+// up to maxRedials times. An endpoint without subscriptions, such as HTTP, is
+// polled for its latest header instead. A polled or subscribed head may skip
+// blocks, the watcher fetches them by their parent hash.
+func watchHeads(ctx context.Context, url string, headers chan<- *types.Header) (*ethclient.Client, ethereum.Subscription) {
+	for attempt := 1; ; attempt++ {
+		client, sub, err := subscribeHeads(ctx, url, headers)
+		if err == nil {
+			return client, sub
+		}
+		if attempt >= maxRedials {
+			log.Fatalf("Fail to watch the heads of %v: %v", url, err)
+		}
+		log.Printf("watching the heads of %v: %v, retrying\n", url, err)
+		time.Sleep(redialBackoff)
+	}
+}
+
+func subscribeHeads(ctx context.Context, url string, headers chan<- *types.Header) (*ethclient.Client, ethereum.Subscription, error) {
+	client, err := ethclient.DialContext(ctx, url)
+	if err != nil {
+		return nil, nil, err
+	}
+	sub, err := client.SubscribeNewHead(ctx, headers)
+	if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		log.Printf("%v does not support subscriptions, polling every %v\n", url, headPollInterval)
+		return client, pollHeads(client, headers, headPollInterval), nil
+	}
+	if err != nil {
+		client.Close()
+		return nil, nil, err
+	}
+	return client, sub, nil
+}
+
+// headerReader returns the header of a block, the latest one for a nil number.
+type headerReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// pollHeads sends the latest header of client to headers when it changes.
+func pollHeads(client headerReader, headers chan<- *types.Header, interval time.Duration) ethereum.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var last *types.Header
+		for {
+			select {
+			case <-quit:
+				return nil
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval*4)
+				header, err := client.HeaderByNumber(ctx, nil)
+				cancel()
+				if err != nil {
+					return err
+				}
+				if last != nil && header.Hash() == last.Hash() {
+					continue
+				}
+				last = header
+				select {
+				case headers <- header:
+				case <-quit:
+					return nil
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// stubHeads returns its headers in order, then err.
+type stubHeads struct {
+	headers []*types.Header
+	err     error
+}
+
+func (s *stubHeads) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if number != nil {
+		return nil, errors.New("not the latest header")
+	}
+	if len(s.headers) == 0 {
+		return nil, s.err
+	}
+	header := s.headers[0]
+	s.headers = s.headers[1:]
+	return header, nil
+}
+
+func TestPollHeads(t *testing.T) {
+	blocks := make(testChain)
+	b1 := blocks.block(1, nil, "a")
+	b2 := blocks.block(2, b1, "a", reorgTx(1))
+	b3 := blocks.block(3, b2, "a", reorgTx(2))
+	failure := errors.New("connection refused")
+	// the second poll repeats block 1, the third one skips block 2
+	source := &stubHeads{headers: []*types.Header{b1.Header(), b1.Header(), b3.Header()}, err: failure}
+
+	headers := make(chan *types.Header)
+	sub := pollHeads(source, headers, time.Millisecond)
+	defer sub.Unsubscribe()
+
+	tracker := newChainTracker()
+	var numbers []uint64
+	for len(numbers) < 3 {
+		select {
+		case header := <-headers:
+			head, err := blocks.byHash(header.Hash())
+			if err != nil {
+				t.Fatal(err)
+			}
+			added, _, err := tracker.advance(head, blocks.byHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, block := range added {
+				tracker.record(block, 0)
+				numbers = append(numbers, block.NumberU64())
+			}
+		case err := <-sub.Err():
+			t.Fatalf("polling failed after blocks %v: %v", numbers, err)
+		case <-time.After(time.Second):
+			t.Fatalf("blocks %v, want 1, 2 and 3", numbers)
+		}
+	}
+	if numbers[0] != 1 || numbers[1] != 2 || numbers[2] != 3 {
+		t.Errorf("blocks %v, want 1, 2 and 3", numbers)
+	}
+
+	select {
+	case header := <-headers:
+		t.Errorf("repeated or unexpected head %v", header.Number)
+	case err := <-sub.Err():
+		if !errors.Is(err, failure) {
+			t.Errorf("err = %v, want %v", err, failure)
+		}
+	case <-time.After(time.Second):
+		t.Error("poll error not reported")
+	}
+}
@@ -0,0 +1,64 @@
+package benchmark
+
+import "fmt"
+
+// LoadProfile describes how many transactions are sent per second.
+//
+//	constant: Rate tx/s for the whole run
+//	ramp:     StartRate tx/s, increased by Step every second up to EndRate
+type LoadProfile struct {
+	Type      string `yaml:"type"`
+	Rate      int    `yaml:"rate"`
+	StartRate int    `yaml:"startRate"`
+	EndRate   int    `yaml:"endRate"`
+	Step      int    `yaml:"step"`
+}
+
+func ConstantRate(rate int) LoadProfile {
+	return LoadProfile{Type: "constant", Rate: rate}
+}
+
+func (p LoadProfile) Validate() error {
+	switch p.Type {
+	case "", "constant":
+		if p.Rate <= 0 {
+			return fmt.Errorf("constant profile needs a positive rate, got %d", p.Rate)
+		}
+	case "ramp":
+		if p.StartRate <= 0 || p.EndRate < p.StartRate || p.Step <= 0 {
+			return fmt.Errorf("ramp profile needs 0 < startRate <= endRate and a positive step")
+		}
+	default:
+		return fmt.Errorf("unknown load profile type %q", p.Type)
+	}
+	return nil
+}
+
+// RateAt returns the send rate for the given second since the start of the run.
+func (p LoadProfile) RateAt(second int) int {
+	if p.Type == "ramp" {
+		rate := p.StartRate + p.Step*second
+		if rate > p.EndRate {
+			rate = p.EndRate
+		}
+		return rate
+	}
+	return p.Rate
+}
+
+// NominalRate is the highest rate reached by the profile. It is used in result file names.
+func (p LoadProfile) NominalRate() int {
+	if p.Type == "ramp" {
+		return p.EndRate
+	}
+	return p.Rate
+}
+
+// CountFor returns how many transactions the profile sends in the given number of seconds.
+func (p LoadProfile) CountFor(seconds int) int {
+	count := 0
+	for s := 0; s < seconds; s++ {
+		count += p.RateAt(s)
+	}
+	return count
+}
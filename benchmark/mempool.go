@@ -0,0 +1,276 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// MempoolReport is the pool of the submit node sampled every Interval seconds.
+// When the node serves txpool_inspect or txpool_content, the transactions of
+// the benchmark senders are followed: the seconds they spent pending and
+// queued, and the nonce gaps that kept them queued.
+type MempoolReport struct {
+	Interval  float64         `json:"interval"`
+	Content   bool            `json:"content"`
+	Samples   []MempoolSample `json:"samples"`
+	Pending   *DurationStats  `json:"pending,omitempty"`
+	Queued    *DurationStats  `json:"queued,omitempty"`
+	NonceGaps []NonceGap      `json:"nonce_gaps,omitempty"`
+}
+
+// MempoolSample holds the pool counts at Elapsed seconds, the own counts only
+// with Content.
+type MempoolSample struct {
+	Elapsed    float64 `json:"elapsed"`
+	Pending    int     `json:"pending"`
+	Queued     int     `json:"queued"`
+	OwnPending int     `json:"own_pending"`
+	OwnQueued  int     `json:"own_queued"`
+}
+
+// DurationStats is the distribution in seconds over Count transactions.
+type DurationStats struct {
+	Count int     `json:"count"`
+	Avg   float64 `json:"avg"`
+	P50   float64 `json:"p50"`
+	P95   float64 `json:"p95"`
+	Max   float64 `json:"max"`
+}
+
+// NonceGap is a sender whose lowest queued nonce was above the next
+// executable one from First until Last seconds.
+type NonceGap struct {
+	Sender   common.Address `json:"sender"`
+	Expected uint64         `json:"expected"`
+	Queued   uint64         `json:"queued"`
+	First    float64        `json:"first"`
+	Last     float64        `json:"last"`
+}
+
+// poolContent is the shape of txpool_inspect and txpool_content:
+// status -> sender -> nonce -> transaction.
+type poolContent map[string]map[string]map[string]json.RawMessage
+
+type poolKey struct {
+	sender common.Address
+	nonce  uint64
+}
+
+// mempoolTracker accumulates the samples and the seconds each own transaction
+// was seen pending and queued.
+type mempoolTracker struct {
+	senders map[common.Address]bool
+	samples []MempoolSample
+	pending map[poolKey]float64
+	queued  map[poolKey]float64
+	gaps    map[poolKey]*NonceGap
+}
+
+func newMempoolTracker(senders []common.Address) *mempoolTracker {
+	t := &mempoolTracker{
+		senders: make(map[common.Address]bool),
+		pending: make(map[poolKey]float64),
+		queued:  make(map[poolKey]float64),
+		gaps:    make(map[poolKey]*NonceGap),
+	}
+	for _, sender := range senders {
+		t.senders[sender] = true
+	}
+	return t
+}
+
+// own returns the nonces of the benchmark senders in one status of content.
+func (t *mempoolTracker) own(txs map[string]map[string]json.RawMessage) map[common.Address][]uint64 {
+	nonces := make(map[common.Address][]uint64)
+	for account, byNonce := range txs {
+		sender := common.HexToAddress(account)
+		if !t.senders[sender] {
+			continue
+		}
+		for n := range byNonce {
+			nonce, err := strconv.ParseUint(n, 10, 64)
+			if err != nil {
+				continue
+			}
+			nonces[sender] = append(nonces[sender], nonce)
+		}
+		slices.Sort(nonces[sender])
+	}
+	return nonces
+}
+
+// observe records a sample taken step seconds after the previous one.
+// nonceAt returns the account nonce of a sender without pending transactions.
+func (t *mempoolTracker) observe(elapsed float64, step float64, pending int, queued int, content poolContent, nonceAt func(common.Address) uint64) {
+	sample := MempoolSample{Elapsed: elapsed, Pending: pending, Queued: queued}
+	if content != nil {
+		ownPending, ownQueued := t.own(content["pending"]), t.own(content["queued"])
+		for sender, nonces := range ownPending {
+			sample.OwnPending += len(nonces)
+			for _, nonce := range nonces {
+				t.pending[poolKey{sender, nonce}] += step
+			}
+		}
+		for sender, nonces := range ownQueued {
+			sample.OwnQueued += len(nonces)
+			for _, nonce := range nonces {
+				t.queued[poolKey{sender, nonce}] += step
+			}
+			// without pending transactions the next executable nonce is the account nonce
+			var expected uint64
+			if executable := ownPending[sender]; len(executable) > 0 {
+				expected = executable[len(executable)-1] + 1
+			} else if nonceAt != nil {
+				expected = nonceAt(sender)
+			} else {
+				continue
+			}
+			if nonces[0] > expected {
+				key := poolKey{sender, expected}
+				if gap, ok := t.gaps[key]; ok {
+					gap.Last = elapsed
+				} else {
+					t.gaps[key] = &NonceGap{Sender: sender, Expected: expected, Queued: nonces[0], First: elapsed, Last: elapsed}
+				}
+			}
+		}
+	}
+	t.samples = append(t.samples, sample)
+}
+
+func durationStats(durations map[poolKey]float64) *DurationStats {
+	if len(durations) == 0 {
+		return nil
+	}
+	values := make([]float64, 0, len(durations))
+	total := 0.0
+	for _, d := range durations {
+		values = append(values, d)
+		total += d
+	}
+	return &DurationStats{
+		Count: len(values),
+		Avg:   total / float64(len(values)),
+		P50:   percentile(values, 50),
+		P95:   percentile(values, 95),
+		Max:   percentile(values, 100),
+	}
+}
+
+func (t *mempoolTracker) report(interval time.Duration, content bool) *MempoolReport {
+	report := &MempoolReport{
+		Interval: interval.Seconds(),
+		Content:  content,
+		Samples:  t.samples,
+		Pending:  durationStats(t.pending),
+		Queued:   durationStats(t.queued),
+	}
+	for _, gap := range t.gaps {
+		report.NonceGaps = append(report.NonceGaps, *gap)
+	}
+	slices.SortFunc(report.NonceGaps, func(a, b NonceGap) int {
+		if a.First != b.First {
+			if a.First < b.First {
+				return -1
+			}
+			return 1
+		}
+		return a.Sender.Cmp(b.Sender)
+	})
+	return report
+}
+
+// mempoolSampler polls the pool of one node until stopped.
+type mempoolSampler struct {
+	interval time.Duration
+	tracker  *mempoolTracker
+	content  bool
+	cancel   context.CancelFunc
+	wait     sync.WaitGroup
+}
+
+// newMempoolSampler samples the pool every interval, or returns nil when the
+// interval is 0. With content, the transactions of senders in the pool are
+// followed as well.
+func newMempoolSampler(client *rpc.Client, senders []common.Address, interval time.Duration, content bool) *mempoolSampler {
+	if interval <= 0 {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &mempoolSampler{interval: interval, tracker: newMempoolTracker(senders), content: content, cancel: cancel}
+	m.wait.Add(1)
+	go func() {
+		defer m.wait.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		start, last := time.Now(), time.Now()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				m.sample(ctx, client, now.Sub(start).Seconds(), now.Sub(last).Seconds())
+				last = now
+			}
+		}
+	}()
+	return m
+}
+
+func (m *mempoolSampler) sample(ctx context.Context, client *rpc.Client, elapsed float64, step float64) {
+	var status map[string]string
+	if err := client.CallContext(ctx, &status, "txpool_status"); err != nil {
+		if ctx.Err() == nil {
+			log.Println("txpool_status:", err)
+		}
+		return
+	}
+	pending, _ := strconv.ParseInt(status["pending"], 0, 64)
+	queued, _ := strconv.ParseInt(status["queued"], 0, 64)
+
+	var content poolContent
+	if m.content {
+		if err := client.CallContext(ctx, &content, "txpool_inspect"); err != nil {
+			content = nil
+			if err = client.CallContext(ctx, &content, "txpool_content"); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Println("txpool content is not available, sampling the counts only:", err)
+				m.content, content = false, nil
+			}
+		}
+	}
+	nonceAt := func(sender common.Address) uint64 {
+		var nonce hexutil.Uint64
+		client.CallContext(ctx, &nonce, "eth_getTransactionCount", sender, "latest")
+		return uint64(nonce)
+	}
+	m.tracker.observe(elapsed, step, int(pending), int(queued), content, nonceAt)
+}
+
+// stop ends the sampling and reports the pool over the run.
+func (m *mempoolSampler) stop() *MempoolReport {
+	if m == nil {
+		return nil
+	}
+	m.cancel()
+	m.wait.Wait()
+	report := m.tracker.report(m.interval, m.content)
+	if report.Pending != nil {
+		log.Printf("mempool: %v transactions pending for avg %.2fs\n", report.Pending.Count, report.Pending.Avg)
+	}
+	if report.Queued != nil {
+		log.Printf("mempool: %v transactions queued for avg %.2fs, %v nonce gaps\n", report.Queued.Count, report.Queued.Avg, len(report.NonceGaps))
+	}
+	return report
+}
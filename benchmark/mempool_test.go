@@ -0,0 +1,59 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func poolOf(status map[string]map[common.Address][]string) poolContent {
+	content := make(poolContent)
+	for s, accounts := range status {
+		content[s] = make(map[string]map[string]json.RawMessage)
+		for account, nonces := range accounts {
+			byNonce := make(map[string]json.RawMessage)
+			for _, nonce := range nonces {
+				byNonce[nonce] = json.RawMessage(`"0x0: 0 wei + 21000 gas × 1 wei"`)
+			}
+			content[s][account.Hex()] = byNonce
+		}
+	}
+	return content
+}
+
+func TestMempoolTracker(t *testing.T) {
+	a, b, other := common.Address{1}, common.Address{2}, common.Address{3}
+	tracker := newMempoolTracker([]common.Address{a, b})
+	nonceAt := func(common.Address) uint64 { return 5 }
+
+	tracker.observe(1, 1, 10, 3, poolOf(map[string]map[common.Address][]string{
+		"pending": {a: {"0", "1"}, other: {"0"}},
+		"queued":  {a: {"3"}, b: {"7"}},
+	}), nonceAt)
+	tracker.observe(2, 1, 8, 3, poolOf(map[string]map[common.Address][]string{
+		"pending": {a: {"1"}},
+		"queued":  {a: {"3"}, b: {"7"}},
+	}), nonceAt)
+	tracker.observe(3, 1, 4, 0, nil, nonceAt)
+
+	report := tracker.report(1e9, true)
+	if len(report.Samples) != 3 || report.Samples[0].OwnPending != 2 || report.Samples[0].OwnQueued != 2 || report.Samples[1].Pending != 8 {
+		t.Errorf("samples = %+v", report.Samples)
+	}
+	if report.Pending.Count != 2 || report.Pending.Max != 2 || report.Pending.Avg != 1.5 {
+		t.Errorf("pending = %+v", report.Pending)
+	}
+	if report.Queued.Count != 2 || report.Queued.Avg != 2 {
+		t.Errorf("queued = %+v", report.Queued)
+	}
+	if len(report.NonceGaps) != 2 {
+		t.Fatalf("nonce gaps = %+v", report.NonceGaps)
+	}
+	if gap := report.NonceGaps[0]; gap.Sender != a || gap.Expected != 2 || gap.Queued != 3 || gap.First != 1 || gap.Last != 2 {
+		t.Errorf("gap of a = %+v", gap)
+	}
+	if gap := report.NonceGaps[1]; gap.Sender != b || gap.Expected != 5 || gap.Queued != 7 {
+		t.Errorf("gap of b = %+v", gap)
+	}
+}
@@ -0,0 +1,466 @@
+package benchmark
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"decipher.com/tps/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testNode is an in-process chain serving the eth methods of a run over a
+// websocket. It mines the pending transactions in a new block every
+// testBlockInterval, one second after its parent.
+type testNode struct {
+	URL      string
+	chain    *core.BlockChain
+	db       ethdb.Database
+	config   *params.ChainConfig
+	signer   types.Signer
+	heads    event.Feed
+	mutex    sync.Mutex
+	pending  map[common.Address][]*types.Transaction
+	included map[common.Hash]common.Hash
+}
+
+const testBlockInterval = 100 * time.Millisecond
+
+// newTestNode starts a node whose genesis funds the accounts of keys.
+func newTestNode(t *testing.T, keys ...*ecdsa.PrivateKey) *testNode {
+	t.Helper()
+	chainConfig := *params.AllDevChainProtocolChanges
+	chainConfig.ChainID = config.ChainID
+	genesis := &core.Genesis{
+		Config:     &chainConfig,
+		Timestamp:  uint64(time.Now().Unix()),
+		GasLimit:   30000000,
+		Difficulty: common.Big0,
+		BaseFee:    big.NewInt(params.InitialBaseFee),
+		Alloc:      make(core.GenesisAlloc),
+	}
+	for _, key := range keys {
+		genesis.Alloc[crypto.PubkeyToAddress(key.PublicKey)] = core.GenesisAccount{Balance: new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))}
+	}
+	db := rawdb.NewMemoryDatabase()
+	cache := &core.CacheConfig{TrieCleanLimit: 16, TrieDirtyDisabled: true, StateScheme: rawdb.HashScheme}
+	chain, err := core.NewBlockChain(db, cache, genesis, nil, beacon.New(ethash.NewFaker()), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &testNode{
+		chain:    chain,
+		db:       db,
+		config:   &chainConfig,
+		signer:   types.LatestSignerForChainID(config.ChainID),
+		pending:  make(map[common.Address][]*types.Transaction),
+		included: make(map[common.Hash]common.Hash),
+	}
+
+	server := rpc.NewServer()
+	if err = server.RegisterName("eth", n); err != nil {
+		t.Fatal(err)
+	}
+	http := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	n.URL = "ws" + strings.TrimPrefix(http.URL, "http")
+
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(testBlockInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-quit:
+				return
+			case <-ticker.C:
+				if err := n.mine(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		close(quit)
+		<-done
+		http.Close()
+		server.Stop()
+		chain.Stop()
+	})
+	return n
+}
+
+// runner returns a Runner of total transactions sent by key to the node.
+func (n *testNode) runner(key *ecdsa.PrivateKey, accounts []*ecdsa.PrivateKey, total int) *Runner {
+	return &Runner{
+		Key:          key,
+		Accounts:     accounts,
+		Total:        total,
+		Endpoints:    []config.Endpoint{{URL: n.URL, Role: "both", Transport: "ws"}},
+		Strategy:     "round-robin",
+		Connections:  1,
+		CountMode:    "events",
+		DrainTimeout: 30 * time.Second,
+	}
+}
+
+// submit queues txs at once, so that the ones whose nonces follow are mined
+// in the same block.
+func (n *testNode) submit(txs ...*types.Transaction) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for _, tx := range txs {
+		from, _ := types.Sender(n.signer, tx)
+		n.pending[from] = append(n.pending[from], tx)
+	}
+}
+
+// mine includes the pending transactions that follow the nonces of their
+// senders in a new block. Transactions that cannot be executed are dropped.
+func (n *testNode) mine() error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if len(n.pending) == 0 {
+		return nil
+	}
+	parent := n.chain.GetBlockByHash(n.chain.CurrentBlock().Hash())
+	statedb, err := n.chain.StateAt(parent.Root())
+	if err != nil {
+		return err
+	}
+	blocks, _ := core.GenerateChain(n.config, parent, n.chain.Engine(), n.db, 1, func(i int, b *core.BlockGen) {
+		b.OffsetTime(-9)
+		b.SetPoS()
+		for from, txs := range n.pending {
+			slices.SortFunc(txs, func(a, b *types.Transaction) int { return int(a.Nonce()) - int(b.Nonce()) })
+			nonce := statedb.GetNonce(from)
+			var kept []*types.Transaction
+			for _, tx := range txs {
+				switch {
+				case tx.Nonce() > nonce:
+					kept = append(kept, tx)
+				case tx.Nonce() == nonce && addTx(b, tx):
+					nonce++
+				}
+			}
+			n.pending[from] = kept
+			if len(kept) == 0 {
+				delete(n.pending, from)
+			}
+		}
+	})
+	if _, err = n.chain.InsertChain(blocks); err != nil {
+		return err
+	}
+	for _, tx := range blocks[0].Transactions() {
+		n.included[tx.Hash()] = blocks[0].Hash()
+	}
+	n.heads.Send(blocks[0].Header())
+	return nil
+}
+
+// addTx adds tx to the block, it reports false if tx cannot be executed.
+func addTx(b *core.BlockGen, tx *types.Transaction) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	b.AddTx(tx)
+	return true
+}
+
+func (n *testNode) header(number rpc.BlockNumber) *types.Header {
+	if number < 0 {
+		return n.chain.CurrentBlock()
+	}
+	return n.chain.GetHeaderByNumber(uint64(number))
+}
+
+func (n *testNode) state(number rpc.BlockNumber) (*types.Header, *state.StateDB, error) {
+	header := n.header(number)
+	if header == nil {
+		return nil, nil, fmt.Errorf("block %d not found", number)
+	}
+	statedb, err := n.chain.StateAt(header.Root)
+	return header, statedb, err
+}
+
+// balance returns the balance of account at the head.
+func (n *testNode) balance(account common.Address) *big.Int {
+	_, statedb, err := n.state(rpc.LatestBlockNumber)
+	if err != nil {
+		return nil
+	}
+	return statedb.GetBalance(account).ToBig()
+}
+
+func (n *testNode) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(n.config.ChainID)
+}
+
+func (n *testNode) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(2 * params.GWei))
+}
+
+func (n *testNode) GetTransactionCount(account common.Address, number string) (hexutil.Uint64, error) {
+	_, statedb, err := n.state(rpc.LatestBlockNumber)
+	if err != nil {
+		return 0, err
+	}
+	nonce := statedb.GetNonce(account)
+	if number != "pending" {
+		return hexutil.Uint64(nonce), nil
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for slices.ContainsFunc(n.pending[account], func(tx *types.Transaction) bool { return tx.Nonce() == nonce }) {
+		nonce++
+	}
+	return hexutil.Uint64(nonce), nil
+}
+
+func (n *testNode) SendRawTransaction(input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	from, err := types.Sender(n.signer, tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if _, ok := n.included[tx.Hash()]; ok || slices.ContainsFunc(n.pending[from], func(pending *types.Transaction) bool { return pending.Hash() == tx.Hash() }) {
+		return common.Hash{}, errors.New("already known")
+	}
+	n.pending[from] = append(n.pending[from], tx)
+	return tx.Hash(), nil
+}
+
+func (n *testNode) GetTransactionReceipt(hash common.Hash) (*types.Receipt, error) {
+	n.mutex.Lock()
+	block, ok := n.included[hash]
+	n.mutex.Unlock()
+	if !ok {
+		return nil, nil
+	}
+	for _, receipt := range n.chain.GetReceiptsByHash(block) {
+		if receipt.TxHash == hash {
+			return receipt, nil
+		}
+	}
+	return nil, nil
+}
+
+func (n *testNode) GetBlockByHash(hash common.Hash, full bool) (map[string]interface{}, error) {
+	return marshalBlock(n.chain.GetBlockByHash(hash))
+}
+
+func (n *testNode) GetBlockByNumber(number rpc.BlockNumber, full bool) (map[string]interface{}, error) {
+	header := n.header(number)
+	if header == nil {
+		return nil, nil
+	}
+	return marshalBlock(n.chain.GetBlockByHash(header.Hash()))
+}
+
+// marshalBlock returns the fields of block with its full transactions.
+func marshalBlock(block *types.Block) (map[string]interface{}, error) {
+	if block == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(block.Header())
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	if err = json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	fields["transactions"] = block.Transactions()
+	fields["uncles"] = []common.Hash{}
+	if block.Withdrawals() != nil {
+		fields["withdrawals"] = block.Withdrawals()
+	}
+	return fields, nil
+}
+
+func (n *testNode) GetBalance(account common.Address, number rpc.BlockNumber) (*hexutil.Big, error) {
+	_, statedb, err := n.state(number)
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(statedb.GetBalance(account).ToBig()), nil
+}
+
+func (n *testNode) GetCode(account common.Address, number rpc.BlockNumber) (hexutil.Bytes, error) {
+	_, statedb, err := n.state(number)
+	if err != nil {
+		return nil, err
+	}
+	return statedb.GetCode(account), nil
+}
+
+type callArgs struct {
+	From  *common.Address `json:"from"`
+	To    *common.Address `json:"to"`
+	Gas   *hexutil.Uint64 `json:"gas"`
+	Value *hexutil.Big    `json:"value"`
+	Input hexutil.Bytes   `json:"input"`
+	Data  hexutil.Bytes   `json:"data"`
+}
+
+// revertError carries the revert data of a call as the nodes do.
+type revertError struct {
+	data []byte
+}
+
+func (e *revertError) Error() string          { return "execution reverted" }
+func (e *revertError) ErrorCode() int         { return 3 }
+func (e *revertError) ErrorData() interface{} { return hexutil.Encode(e.data) }
+
+// call executes args without a gas price on the state of block number.
+func (n *testNode) call(args callArgs, number rpc.BlockNumber) (*core.ExecutionResult, error) {
+	header, statedb, err := n.state(number)
+	if err != nil {
+		return nil, err
+	}
+	msg := &core.Message{
+		To:                args.To,
+		Value:             new(big.Int),
+		GasLimit:          header.GasLimit,
+		GasPrice:          new(big.Int),
+		GasFeeCap:         new(big.Int),
+		GasTipCap:         new(big.Int),
+		Data:              args.Input,
+		SkipAccountChecks: true,
+	}
+	if args.From != nil {
+		msg.From = *args.From
+	}
+	if args.Gas != nil {
+		msg.GasLimit = uint64(*args.Gas)
+	}
+	if args.Value != nil {
+		msg.Value = args.Value.ToInt()
+	}
+	if msg.Data == nil {
+		msg.Data = args.Data
+	}
+	evm := vm.NewEVM(core.NewEVMBlockContext(header, n.chain, nil), core.NewEVMTxContext(msg), statedb, n.config, vm.Config{NoBaseFee: true})
+	result, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(math.MaxUint64))
+	if err != nil {
+		return nil, err
+	}
+	if errors.Is(result.Err, vm.ErrExecutionReverted) {
+		return nil, &revertError{data: result.Revert()}
+	}
+	return result, result.Err
+}
+
+func (n *testNode) Call(args callArgs, number rpc.BlockNumber) (hexutil.Bytes, error) {
+	result, err := n.call(args, number)
+	if err != nil {
+		return nil, err
+	}
+	return result.Return(), nil
+}
+
+func (n *testNode) EstimateGas(args callArgs, number *rpc.BlockNumber) (hexutil.Uint64, error) {
+	result, err := n.call(args, rpc.LatestBlockNumber)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(result.UsedGas * 3 / 2), nil
+}
+
+type filterArgs struct {
+	FromBlock rpc.BlockNumber  `json:"fromBlock"`
+	ToBlock   rpc.BlockNumber  `json:"toBlock"`
+	Address   []common.Address `json:"address"`
+	Topics    [][]common.Hash  `json:"topics"`
+}
+
+// GetLogs returns the logs of the blocks in range emitted by the addresses
+// with the topics of args, an empty topic position matches any topic.
+func (n *testNode) GetLogs(args filterArgs) ([]*types.Log, error) {
+	from, to := n.header(args.FromBlock), n.header(args.ToBlock)
+	if from == nil || to == nil {
+		return nil, errors.New("block not found")
+	}
+	logs := []*types.Log{}
+	for number := from.Number.Uint64(); number <= to.Number.Uint64(); number++ {
+		for _, receipt := range n.chain.GetReceiptsByHash(n.chain.GetCanonicalHash(number)) {
+			for _, log := range receipt.Logs {
+				if matchLog(log, args) {
+					logs = append(logs, log)
+				}
+			}
+		}
+	}
+	return logs, nil
+}
+
+func matchLog(log *types.Log, args filterArgs) bool {
+	if len(args.Address) > 0 && !slices.Contains(args.Address, log.Address) {
+		return false
+	}
+	if len(args.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, topics := range args.Topics {
+		if len(topics) > 0 && !slices.Contains(topics, log.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewHeads sends the header of every mined block to an eth_subscribe("newHeads") subscription.
+func (n *testNode) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	subscription := notifier.CreateSubscription()
+	headers := make(chan *types.Header, 16)
+	sub := n.heads.Subscribe(headers)
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case header := <-headers:
+				notifier.Notify(subscription.ID, header)
+			case <-subscription.Err():
+				return
+			}
+		}
+	}()
+	return subscription, nil
+}
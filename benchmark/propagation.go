@@ -0,0 +1,173 @@
+package benchmark
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"decipher.com/tps/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// PropagationReport is the time from submission until a transaction appears
+// in each node's pool, and between the pools of each pair of nodes. It is
+// separate from the inclusion latency of the result.
+type PropagationReport struct {
+	Nodes []string           `json:"nodes"`
+	Pairs []PropagationStats `json:"pairs"`
+}
+
+// PropagationStats is the delay distribution in seconds from From to To. From
+// is "submit" for the time the transaction was sent. Delays between two pools
+// are negative when To saw the transaction first. Missing counts the
+// transactions To never announced.
+type PropagationStats struct {
+	From    string  `json:"from"`
+	To      string  `json:"to"`
+	Count   int     `json:"count"`
+	Missing int     `json:"missing"`
+	Avg     float64 `json:"avg"`
+	P50     float64 `json:"p50"`
+	P95     float64 `json:"p95"`
+	P99     float64 `json:"p99"`
+	Max     float64 `json:"max"`
+}
+
+// propagationTracker subscribes to newPendingTransactions on every node and
+// records when each hash is announced first.
+type propagationTracker struct {
+	nodes     []string
+	mutex     sync.Mutex
+	submitted map[common.Hash]time.Time
+	seen      []map[common.Hash]time.Time
+	cancel    context.CancelFunc
+	wait      sync.WaitGroup
+}
+
+// nodes returns the URLs of endpoints that support subscriptions.
+func nodes(endpoints []config.Endpoint) []string {
+	var urls []string
+	for _, endpoint := range endpoints {
+		if endpoint.Transport != "http" && !slices.Contains(urls, endpoint.URL) {
+			urls = append(urls, endpoint.URL)
+		}
+	}
+	return urls
+}
+
+func newPropagationTracker(endpoints []string) *propagationTracker {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &propagationTracker{
+		nodes:     endpoints,
+		submitted: make(map[common.Hash]time.Time),
+		seen:      make([]map[common.Hash]time.Time, len(endpoints)),
+		cancel:    cancel,
+	}
+	for i, endpoint := range endpoints {
+		p.seen[i] = make(map[common.Hash]time.Time)
+		client, err := rpc.DialContext(ctx, endpoint)
+		if err != nil {
+			log.Fatalf("Failed to dial %v: %v", endpoint, err)
+		}
+		hashes := make(chan common.Hash, 4096)
+		sub, err := client.EthSubscribe(ctx, hashes, "newPendingTransactions")
+		if err != nil {
+			log.Fatalf("Failed to subscribe to pending transactions of %v: %v", endpoint, err)
+		}
+		p.wait.Add(1)
+		go func(i int) {
+			defer p.wait.Done()
+			defer client.Close()
+			defer sub.Unsubscribe()
+			for {
+				select {
+				case hash := <-hashes:
+					now := time.Now()
+					p.mutex.Lock()
+					if _, ok := p.seen[i][hash]; !ok {
+						p.seen[i][hash] = now
+					}
+					p.mutex.Unlock()
+				case err := <-sub.Err():
+					log.Printf("pending transaction subscription of %v: %v\n", p.nodes[i], err)
+					return
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(i)
+	}
+	return p
+}
+
+// submit records the time a transaction was sent.
+func (p *propagationTracker) submit(hash common.Hash, at time.Time) {
+	if p == nil {
+		return
+	}
+	p.mutex.Lock()
+	p.submitted[hash] = at
+	p.mutex.Unlock()
+}
+
+// stop ends the subscriptions and reports the delays of the submitted transactions.
+func (p *propagationTracker) stop() *PropagationReport {
+	if p == nil {
+		return nil
+	}
+	p.cancel()
+	p.wait.Wait()
+	report := propagationReport(p.nodes, p.submitted, p.seen)
+	for _, pair := range report.Pairs {
+		log.Printf("propagation %v -> %v: p50 %.4fs, p95 %.4fs, max %.4fs, %v missing\n", pair.From, pair.To, pair.P50, pair.P95, pair.Max, pair.Missing)
+	}
+	return report
+}
+
+func propagationReport(nodes []string, submitted map[common.Hash]time.Time, seen []map[common.Hash]time.Time) *PropagationReport {
+	report := &PropagationReport{Nodes: nodes}
+	pair := func(from string, to string, delay func(hash common.Hash) (time.Duration, bool)) PropagationStats {
+		stats := PropagationStats{From: from, To: to}
+		var delays []float64
+		for hash := range submitted {
+			if d, ok := delay(hash); ok {
+				delays = append(delays, d.Seconds())
+			} else {
+				stats.Missing++
+			}
+		}
+		stats.Count = len(delays)
+		for _, d := range delays {
+			stats.Avg += d
+		}
+		if stats.Count > 0 {
+			stats.Avg /= float64(stats.Count)
+		}
+		stats.P50 = percentile(delays, 50)
+		stats.P95 = percentile(delays, 95)
+		stats.P99 = percentile(delays, 99)
+		stats.Max = percentile(delays, 100)
+		return stats
+	}
+
+	for i, node := range nodes {
+		report.Pairs = append(report.Pairs, pair("submit", node, func(hash common.Hash) (time.Duration, bool) {
+			at, ok := seen[i][hash]
+			return at.Sub(submitted[hash]), ok
+		}))
+	}
+	for i := range nodes {
+		for j := i + 1; j < len(nodes); j++ {
+			from, to := seen[i], seen[j]
+			report.Pairs = append(report.Pairs, pair(nodes[i], nodes[j], func(hash common.Hash) (time.Duration, bool) {
+				a, ok := from[hash]
+				b, ok2 := to[hash]
+				return b.Sub(a), ok && ok2
+			}))
+		}
+	}
+	return report
+}
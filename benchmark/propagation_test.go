@@ -0,0 +1,37 @@
+package benchmark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestPropagationReport(t *testing.T) {
+	start := time.Now()
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+	a, b, c := common.Hash{1}, common.Hash{2}, common.Hash{3}
+	other := common.Hash{9}
+
+	submitted := map[common.Hash]time.Time{a: at(0), b: at(0), c: at(0)}
+	seen := []map[common.Hash]time.Time{
+		{a: at(10), b: at(20), c: at(30), other: at(1)},
+		{a: at(110), b: at(10)},
+	}
+	report := propagationReport([]string{"node1", "node2"}, submitted, seen)
+	if len(report.Pairs) != 3 {
+		t.Fatalf("pairs = %+v", report.Pairs)
+	}
+
+	toNode1, toNode2, between := report.Pairs[0], report.Pairs[1], report.Pairs[2]
+	if toNode1.From != "submit" || toNode1.Count != 3 || toNode1.Missing != 0 || toNode1.Max != 0.03 {
+		t.Errorf("submit -> node1 = %+v", toNode1)
+	}
+	if toNode2.Count != 2 || toNode2.Missing != 1 || toNode2.P50 != 0.01 || toNode2.Max != 0.11 {
+		t.Errorf("submit -> node2 = %+v", toNode2)
+	}
+	// b reached node2 before node1
+	if between.From != "node1" || between.To != "node2" || between.Count != 2 || between.P50 != -0.01 || between.Max != 0.1 {
+		t.Errorf("node1 -> node2 = %+v", between)
+	}
+}
@@ -0,0 +1,225 @@
+package benchmark
+
+import (
+	"fmt"
+	"log"
+
+	"decipher.com/tps/config"
+	"github.com/spf13/pflag"
+)
+
+// Definition registers a workload under the name of its command and of the
+// scenario workload. Another module adds its workloads by registering them
+// from an init function of a package imported by its main package.
+type Definition struct {
+	Name  string
+	Short string
+	Long  string
+	// Accounts returns the number of accounts to load for total transactions,
+	// total when nil.
+	Accounts func(total int) int
+	// Flags binds the options of the workload to the flags of its command, may be nil.
+	Flags func(flags *pflag.FlagSet)
+	// Required lists the flags of Flags the command needs. A group of several
+	// names needs at least one of them.
+	Required [][]string
+	// SupportsContention and SupportsVerify tell whether the workload routes
+	// transactions to the hot state and checks the on-chain state after a run.
+	// The command offers --contention and --verify, and the scenario phases
+	// contention and verify, only to these workloads.
+	SupportsContention bool
+	SupportsVerify     bool
+	// New returns the workload of one run with the current options.
+	New func() (Workload, error)
+	// Options returns new options holding their defaults, which a scenario
+	// phase decodes from the key Name. nil for workloads without options.
+	Options func() Options
+}
+
+// Options configure a workload in a scenario phase. Validate checks them when
+// the scenario is loaded, Workload returns the workload of the phase once the
+// accounts are loaded. Options that need more accounts than the transactions
+// of the phase also have a method AccountsFor(total int) int.
+type Options interface {
+	Validate() error
+	Workload() (Workload, error)
+}
+
+// Registry holds workload definitions by name, in the order of registration.
+type Registry struct {
+	definitions []Definition
+	names       map[string]int
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]int)}
+}
+
+// Register adds the workload of d. Names are unique.
+func (r *Registry) Register(d Definition) error {
+	if d.Name == "" || d.New == nil {
+		return fmt.Errorf("workload definition %q needs a name and New", d.Name)
+	}
+	if _, ok := r.names[d.Name]; ok {
+		return fmt.Errorf("workload %q is registered twice", d.Name)
+	}
+	r.names[d.Name] = len(r.definitions)
+	r.definitions = append(r.definitions, d)
+	return nil
+}
+
+// Lookup returns the workload registered as name.
+func (r *Registry) Lookup(name string) (Definition, bool) {
+	i, ok := r.names[name]
+	if !ok {
+		return Definition{}, false
+	}
+	return r.definitions[i], true
+}
+
+// Definitions returns the registered workloads in the order of registration.
+func (r *Registry) Definitions() []Definition {
+	return append([]Definition(nil), r.definitions...)
+}
+
+// workloads is the registry of the commands and scenarios.
+var workloads = NewRegistry()
+
+// Register adds the workload of d to the registry of the commands and scenarios.
+func Register(d Definition) error {
+	return workloads.Register(d)
+}
+
+// MustRegister is Register for init functions, it panics on an invalid or
+// duplicate definition.
+func MustRegister(d Definition) {
+	if err := Register(d); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the workload registered as name.
+func Lookup(name string) (Definition, bool) {
+	return workloads.Lookup(name)
+}
+
+// Definitions returns the registered workloads in the order of registration.
+func Definitions() []Definition {
+	return workloads.Definitions()
+}
+
+// AccountsFor returns the number of accounts to load for total transactions.
+func (d Definition) AccountsFor(total int) int {
+	if d.Accounts == nil {
+		return total
+	}
+	return d.Accounts(total)
+}
+
+// Run runs the workload once with r and exits on setup errors, as the commands do.
+func (d Definition) Run(r *Runner, profile LoadProfile) *Result {
+	w, err := d.New()
+	if err != nil {
+		log.Fatalf("Invalid %v workload: %v", d.Name, err)
+	}
+	return run(r, profile, w)
+}
+
+func init() {
+	// the token workloads use the contracts deployed by init
+	for _, d := range []struct {
+		name, short string
+		accounts    func(total int) int
+		new         func() Workload
+		contention  bool
+	}{
+		{"erc20mint", "Mint ERC20 tokens", nil, func() Workload { return ERC20MintWorkload(config.ERC20ADDRESS) }, true},
+		{"erc20transfer", "Transfer ERC20 tokens", nil, func() Workload { return ERC20TransferWorkload(config.ERC20ADDRESS) }, true},
+		{"erc721mint", "Mint ERC721 tokens", nil, func() Workload { return ERC721MintWorkload(config.ERC721ADDRESS) }, true},
+		{"erc721transfer", "Transfer ERC721 tokens", nil, func() Workload { return ERC721TransferWorkload(config.ERC721ADDRESS) }, true},
+		{"erc1155mint", "Mint ERC1155 tokens", nil, func() Workload { return ERC1155MintWorkload(config.ERC1155ADDRESS) }, true},
+		{"erc1155transfer", "Transfer ERC1155 tokens", nil, func() Workload { return ERC1155TransferWorkload(config.ERC1155ADDRESS) }, true},
+		{"erc1155batchtransfer", "Batch transfer ERC1155 tokens", nil, func() Workload { return ERC1155BatchTransferWorkload(config.ERC1155ADDRESS) }, true},
+		{"erc20transferfrom", "Approve a spender and transferFrom ERC20 tokens", func(total int) int { return max(total, 2) }, func() Workload { return ERC20TransferFromWorkload(config.ERC20ADDRESS) }, true},
+		{"erc721safetransfer", "Safe transfer ERC721 tokens to a receiver contract", nil, func() Workload { return ERC721SafeTransferWorkload(config.ERC721ADDRESS) }, true},
+		{"nativetransfer", "Transfer Native Coins", nil, NativeTransferWorkload, true},
+		{"multitransfer", "Transfer Native Coins ", func(total int) int { return max(total, config.Multi) }, MultiTransferWorkload, false},
+	} {
+		newWorkload := d.new
+		MustRegister(Definition{
+			Name:               d.name,
+			Short:              d.short,
+			Accounts:           d.accounts,
+			SupportsContention: d.contention,
+			SupportsVerify:     true,
+			New: func() (Workload, error) {
+				return newWorkload(), nil
+			},
+		})
+	}
+
+	custom := new(CustomOptions)
+	MustRegister(Definition{
+		Name:  "custom",
+		Short: "Call a method of any contract described by an ABI",
+		Long: `Call a method of any contract described by an ABI.
+Arguments are templates that may contain the placeholders
+{sender}, {recipient}, {index}, {random_uint} and {account[n]}.
+Arrays are written as [a,b,c].`,
+		Flags: func(flags *pflag.FlagSet) {
+			flags.StringVar(&custom.ABI, "abi", "", "path of the contract ABI JSON")
+			flags.StringVar(&custom.Bin, "bin", "", "path of the deploy bytecode, deploys a new contract when set")
+			flags.StringVar(&custom.Address, "address", "", "address of an already deployed contract")
+			flags.StringVar(&custom.Method, "method", "", "method to call")
+			flags.StringArrayVar(&custom.Args, "arg", nil, "method argument template, repeat for each argument")
+			flags.StringArrayVar(&custom.ConstructorArgs, "constructor-arg", nil, "constructor argument template, repeat for each argument")
+		},
+		Required: [][]string{{"abi"}, {"method"}, {"bin", "address"}},
+		New: func() (Workload, error) {
+			return custom.Workload()
+		},
+		Options: func() Options {
+			return new(CustomOptions)
+		},
+	})
+
+	deploy := &DeployOptions{Bytecode: "synthetic", Size: 1024}
+	MustRegister(Definition{
+		Name:  "deploy-bench",
+		Short: "Deploy contracts with CREATE or CREATE2 from many senders",
+		Accounts: func(total int) int {
+			return deploy.AccountsFor(total)
+		},
+		Flags: func(flags *pflag.FlagSet) {
+			flags.StringVar(&deploy.Bytecode, "bytecode", "synthetic", "contract to deploy: erc20, erc721, erc1155 or synthetic")
+			flags.IntVar(&deploy.Size, "size", 1024, "runtime code size in bytes of the synthetic contract")
+			flags.BoolVar(&deploy.Create2, "create2", false, "deploy with CREATE2 through a factory contract")
+			flags.IntVar(&deploy.Senders, "senders", 0, "number of sending accounts (default multi.value)")
+		},
+		SupportsVerify: true,
+		New: func() (Workload, error) {
+			return deploy.Workload()
+		},
+		Options: func() Options {
+			return &DeployOptions{Bytecode: "synthetic", Size: 1024}
+		},
+	})
+
+	synthetic := &SyntheticOptions{Kind: "keccak", Intensity: 100}
+	MustRegister(Definition{
+		Name:  "synthetic",
+		Short: "Call a compute, storage, calldata or log heavy synthetic contract",
+		Flags: func(flags *pflag.FlagSet) {
+			flags.StringVar(&synthetic.Kind, "kind", "keccak", "synthetic call: keccak, sstore, calldata or logs")
+			flags.IntVar(&synthetic.Intensity, "intensity", 100, "keccak rounds, fresh storage slots, calldata bytes or logs per transaction")
+		},
+		SupportsVerify: true,
+		New: func() (Workload, error) {
+			return synthetic.Workload()
+		},
+		Options: func() Options {
+			return &SyntheticOptions{Kind: "keccak", Intensity: 100}
+		},
+	})
+}
@@ -0,0 +1,128 @@
+package benchmark
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/pflag"
+)
+
+type testWorkload struct{}
+
+func (testWorkload) Name() string {
+	return "test_workload"
+}
+
+func (testWorkload) Setup(ctx context.Context, bc *BenchmarkContext) error {
+	return nil
+}
+
+func (testWorkload) Transaction(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error) {
+	return nil, nil
+}
+
+func TestRegistry(t *testing.T) {
+	names := []string{"erc20mint", "erc20transfer", "nativetransfer", "multitransfer", "custom", "deploy-bench", "synthetic"}
+	for _, name := range names {
+		if _, ok := Lookup(name); !ok {
+			t.Errorf("%v is not registered", name)
+		}
+	}
+	if _, ok := Lookup("unknown"); ok {
+		t.Error("unknown workload found")
+	}
+
+	registry := NewRegistry()
+	var option string
+	definition := Definition{
+		Name:     "test-workload",
+		Accounts: func(total int) int { return total * 2 },
+		Flags: func(flags *pflag.FlagSet) {
+			flags.StringVar(&option, "option", "", "")
+		},
+		New: func() (Workload, error) { return testWorkload{}, nil },
+	}
+	if err := registry.Register(definition); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register(definition); err == nil {
+		t.Error("registered test-workload twice")
+	}
+	if err := registry.Register(Definition{Name: "no-new"}); err == nil {
+		t.Error("registered a definition without New")
+	}
+	if _, ok := Lookup("test-workload"); ok {
+		t.Error("test-workload found in the default registry")
+	}
+	definitions := registry.Definitions()
+	if len(definitions) != 1 || definitions[0].Name != "test-workload" || definitions[0].AccountsFor(10) != 20 {
+		t.Errorf("definitions = %v", definitions)
+	}
+	d, _ := registry.Lookup("test-workload")
+	flags := pflag.NewFlagSet(d.Name, pflag.ContinueOnError)
+	d.Flags(flags)
+	if err := flags.Parse([]string{"--option", "value"}); err != nil || option != "value" {
+		t.Errorf("option = %q, %v", option, err)
+	}
+	if w, err := d.New(); err != nil || w.Name() != "test_workload" {
+		t.Errorf("new = %v, %v", w, err)
+	}
+	if erc20, _ := Lookup("erc20transferfrom"); erc20.AccountsFor(1) != 2 {
+		t.Errorf("erc20transferfrom accounts = %v", erc20.AccountsFor(1))
+	}
+	if native, _ := Lookup("nativetransfer"); native.AccountsFor(7) != 7 {
+		t.Errorf("nativetransfer accounts = %v", native.AccountsFor(7))
+	}
+}
+
+func TestScenarioOptions(t *testing.T) {
+	load := func(content string) (*Scenario, error) {
+		filename := filepath.Join(t.TempDir(), "scenario.yml")
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return LoadScenario(filename)
+	}
+	scenario, err := load(`
+name: options
+phases:
+  - workload: synthetic
+    count: 10
+    profile: { type: constant, rate: 10 }
+    synthetic: { kind: sstore, intensity: 5 }
+  - workload: deploy-bench
+    count: 10
+    profile: { type: constant, rate: 10 }
+    deploy-bench: { senders: 20 }
+  - workload: erc20mint
+    count: 10
+    profile: { type: constant, rate: 10 }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if options, ok := scenario.Phases[0].options.(*SyntheticOptions); !ok || *options != (SyntheticOptions{Kind: "sstore", Intensity: 5}) {
+		t.Errorf("synthetic options = %v", scenario.Phases[0].options)
+	}
+	if accounts := scenario.Phases[1].accounts(); accounts != 20 {
+		t.Errorf("deploy-bench accounts = %v", accounts)
+	}
+	if scenario.Phases[2].options != nil {
+		t.Errorf("erc20mint options = %v", scenario.Phases[2].options)
+	}
+
+	for _, bad := range []string{
+		"phases:\n  - workload: erc20mint\n    count: 10\n    profile: { rate: 10 }\n    synthetic: { kind: sstore }\n",
+		"phases:\n  - workload: synthetic\n    count: 10\n    profile: { rate: 10 }\n    synthetic: { kind: unknown }\n",
+		"phases:\n  - workload: synthetic\n    count: 10\n    profile: { rate: 10 }\n    synthetic: { rounds: 5 }\n",
+		"phases:\n  - workload: custom\n    count: 10\n    profile: { rate: 10 }\n",
+		"phases:\n  - workload: multitransfer\n    count: 10\n    profile: { rate: 10 }\n    contention: 50\n",
+	} {
+		if _, err := load(bad); err == nil {
+			t.Errorf("accepted %q", bad)
+		}
+	}
+}
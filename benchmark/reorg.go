@@ -0,0 +1,124 @@
+package benchmark
+
+import (
+	"log"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// maxReorgDepth bounds the blocks kept and fetched to find a fork point.
+const maxReorgDepth = 128
+
+// ReorgEvent is a reorganization seen at Elapsed seconds: Depth blocks from
+// Number on were replaced, orphaning their Transactions.
+type ReorgEvent struct {
+	Elapsed      float64     `json:"elapsed"`
+	Number       uint64      `json:"number"`
+	Depth        int         `json:"depth"`
+	OldHead      common.Hash `json:"old_head"`
+	NewHead      common.Hash `json:"new_head"`
+	Transactions int         `json:"transactions"`
+}
+
+// observedBlock is a counted block of the canonical chain.
+type observedBlock struct {
+	number     uint64
+	hash       common.Hash
+	txs        []common.Hash
+	operations int
+}
+
+// chainTracker follows the canonical chain by parent hashes. It keeps the last
+// maxReorgDepth blocks and the orphaned transactions not mined again.
+type chainTracker struct {
+	blocks     map[uint64]*observedBlock
+	tip        *observedBlock
+	orphaned   map[common.Hash]bool
+	reincluded int
+	events     []ReorgEvent
+}
+
+func newChainTracker() *chainTracker {
+	return &chainTracker{blocks: make(map[uint64]*observedBlock), orphaned: make(map[common.Hash]bool)}
+}
+
+// advance returns the blocks from the fork point up to head that are not
+// counted yet, fetching the missing ancestors by hash, and removes the counted
+// blocks they replace. A head that is already counted returns nothing.
+func (c *chainTracker) advance(head *types.Block, blockByHash func(common.Hash) (*types.Block, error)) ([]*types.Block, []*observedBlock, error) {
+	if known := c.blocks[head.NumberU64()]; known != nil && known.hash == head.Hash() {
+		return nil, nil, nil
+	}
+	chain := []*types.Block{head}
+	// a gap above the tip is always filled, a fork point is searched up to maxReorgDepth blocks
+	for c.tip != nil && (len(chain) <= maxReorgDepth || chain[0].NumberU64() > c.tip.number+1) {
+		first := chain[0]
+		if first.NumberU64() == 0 {
+			break
+		}
+		known, number := c.blocks[first.NumberU64()-1], first.NumberU64()-1
+		if known != nil && known.hash == first.ParentHash() {
+			break
+		}
+		// the parent is older than the kept blocks or than the benchmark
+		if known == nil && number <= c.tip.number {
+			break
+		}
+		parent, err := blockByHash(first.ParentHash())
+		if err != nil {
+			return nil, nil, err
+		}
+		chain = append([]*types.Block{parent}, chain...)
+	}
+
+	var orphaned []*observedBlock
+	if c.tip != nil {
+		for number := chain[0].NumberU64(); number <= c.tip.number; number++ {
+			if block := c.blocks[number]; block != nil {
+				orphaned = append(orphaned, block)
+				delete(c.blocks, number)
+				for _, hash := range block.txs {
+					c.orphaned[hash] = true
+				}
+			}
+		}
+	}
+	return chain, orphaned, nil
+}
+
+// record counts block as canonical. Orphaned transactions mined again by it
+// are re-attributed to it.
+func (c *chainTracker) record(block *types.Block, operations int) {
+	observed := &observedBlock{number: block.NumberU64(), hash: block.Hash(), operations: operations}
+	for _, tx := range block.Transactions() {
+		observed.txs = append(observed.txs, tx.Hash())
+		if c.orphaned[tx.Hash()] {
+			delete(c.orphaned, tx.Hash())
+			c.reincluded++
+		}
+	}
+	c.blocks[observed.number] = observed
+	c.tip = observed
+	if observed.number >= maxReorgDepth {
+		delete(c.blocks, observed.number-maxReorgDepth)
+	}
+}
+
+// reorg records the event of a head that orphaned blocks.
+func (c *chainTracker) reorg(elapsed float64, oldHead common.Hash, head *types.Block, orphaned []*observedBlock) {
+	event := ReorgEvent{Elapsed: elapsed, Number: orphaned[0].number, Depth: len(orphaned), OldHead: oldHead, NewHead: head.Hash()}
+	for _, block := range orphaned {
+		event.Transactions += len(block.txs)
+	}
+	log.Printf("reorg at block %v: depth %v, %v transactions orphaned\n", event.Number, event.Depth, event.Transactions)
+	c.events = append(c.events, event)
+}
+
+// summarize adds the reorgs, the orphaned transactions that were not mined
+// again and the re-included ones to result.
+func (c *chainTracker) summarize(result *Result) {
+	result.Reorgs = c.events
+	result.Orphaned = len(c.orphaned)
+	result.Reincluded = c.reincluded
+}
@@ -0,0 +1,104 @@
+package benchmark
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type testChain map[common.Hash]*types.Block
+
+func (c testChain) block(number int64, parent *types.Block, extra string, txs ...*types.Transaction) *types.Block {
+	header := &types.Header{Number: big.NewInt(number), Extra: []byte(extra)}
+	if parent != nil {
+		header.ParentHash = parent.Hash()
+	}
+	block := types.NewBlockWithHeader(header).WithBody(txs, nil)
+	c[block.Hash()] = block
+	return block
+}
+
+func (c testChain) byHash(hash common.Hash) (*types.Block, error) {
+	if block, ok := c[hash]; ok {
+		return block, nil
+	}
+	return nil, fmt.Errorf("unknown block %v", hash)
+}
+
+func reorgTx(nonce uint64) *types.Transaction {
+	return types.NewTx(&types.LegacyTx{Nonce: nonce, GasPrice: big.NewInt(1), Gas: 21000})
+}
+
+func TestChainTrackerReorg(t *testing.T) {
+	blocks := make(testChain)
+	tracker := newChainTracker()
+	follow := func(head *types.Block) ([]*types.Block, []*observedBlock) {
+		added, orphaned, err := tracker.advance(head, blocks.byHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(orphaned) > 0 {
+			tracker.reorg(1, tracker.tip.hash, head, orphaned)
+		}
+		for _, block := range added {
+			tracker.record(block, 0)
+		}
+		return added, orphaned
+	}
+
+	b1 := blocks.block(1, nil, "a")
+	b2 := blocks.block(2, b1, "a", reorgTx(1), reorgTx(2))
+	b3 := blocks.block(3, b2, "a", reorgTx(3))
+	for _, b := range []*types.Block{b1, b2, b3} {
+		if added, orphaned := follow(b); len(added) != 1 || len(orphaned) != 0 {
+			t.Fatalf("block %v: added %v, orphaned %v", b.Number(), len(added), len(orphaned))
+		}
+	}
+	if added, _ := follow(b3); len(added) != 0 {
+		t.Errorf("duplicate head added %v blocks", len(added))
+	}
+
+	// b2 and b3 are replaced by c2 and c3, which re-includes tx 1
+	c2 := blocks.block(2, b1, "c")
+	c3 := blocks.block(3, c2, "c", reorgTx(1))
+	added, orphaned := follow(c3)
+	if len(added) != 2 || added[0] != c2 || len(orphaned) != 2 {
+		t.Fatalf("reorg added %v, orphaned %v", len(added), len(orphaned))
+	}
+	if len(tracker.events) != 1 || tracker.events[0].Number != 2 || tracker.events[0].Depth != 2 || tracker.events[0].Transactions != 3 {
+		t.Errorf("events = %+v", tracker.events)
+	}
+
+	// a missed head is fetched by its parent hash
+	c4 := blocks.block(4, c3, "c")
+	c5 := blocks.block(5, c4, "c")
+	if added, orphaned = follow(c5); len(added) != 2 || added[0] != c4 || len(orphaned) != 0 {
+		t.Errorf("gap added %v, orphaned %v", len(added), len(orphaned))
+	}
+
+	result := &Result{}
+	tracker.summarize(result)
+	if result.Orphaned != 2 || result.Reincluded != 1 || len(result.Reorgs) != 1 {
+		t.Errorf("orphaned = %v, reincluded = %v, reorgs = %v", result.Orphaned, result.Reincluded, len(result.Reorgs))
+	}
+}
+
+func TestChainTrackerBackfill(t *testing.T) {
+	blocks := make(testChain)
+	tracker := newChainTracker()
+	parent := blocks.block(1, nil, "")
+	tracker.record(parent, 0)
+	for number := int64(2); number <= maxReorgDepth+10; number++ {
+		parent = blocks.block(number, parent, "", reorgTx(uint64(number)))
+	}
+	added, orphaned, err := tracker.advance(parent, blocks.byHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != maxReorgDepth+9 || added[0].NumberU64() != 2 || len(orphaned) != 0 {
+		t.Errorf("backfilled %v blocks from %v, orphaned %v", len(added), added[0].NumberU64(), len(orphaned))
+	}
+}
@@ -0,0 +1,102 @@
+package benchmark
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"time"
+
+	"decipher.com/tps/config"
+)
+
+// RepeatOptions controls how a benchmark is repeated. Reset is a shell command
+// run before every trial but the first, e.g. to restart the network and
+// redeploy the contracts; config/config.yml is reloaded after it.
+type RepeatOptions struct {
+	Trials   int
+	Cooldown time.Duration
+	Reset    string
+}
+
+type TrialReport struct {
+	Workload   string    `json:"workload"`
+	Network    string    `json:"network"`
+	Started    time.Time `json:"started"`
+	AvgTPS     Summary   `json:"avg_tps"`
+	MaxTPS     Summary   `json:"max_tps"`
+	SteadyTPS  Summary   `json:"steady_tps"`
+	WindowTPS  Summary   `json:"peak_window_tps"`
+	AvgLatency Summary   `json:"avg_latency"`
+	LatencyP50 Summary   `json:"latency_p50"`
+	LatencyP95 Summary   `json:"latency_p95"`
+	LatencyP99 Summary   `json:"latency_p99"`
+	GasPerSec  Summary   `json:"gas_per_second"`
+	BlockGas   Summary   `json:"block_gas_per_second"`
+	Fullness   Summary   `json:"block_fullness"`
+	Trials     []*Result `json:"trials"`
+}
+
+// Repeat runs the benchmark opts.Trials times and aggregates the results.
+func Repeat(opts RepeatOptions, run func() *Result) *TrialReport {
+	report := &TrialReport{Network: config.Network, Started: time.Now()}
+	for i := 0; i < opts.Trials; i++ {
+		if i > 0 {
+			opts.pause()
+		}
+		log.Printf("===== trial %d/%d =====\n", i+1, opts.Trials)
+		report.Trials = append(report.Trials, run())
+	}
+	report.Workload = report.Trials[0].Workload
+
+	metric := func(get func(*Result) float64) Summary {
+		values := make([]float64, 0, len(report.Trials))
+		for _, result := range report.Trials {
+			values = append(values, get(result))
+		}
+		return summarize(values)
+	}
+	report.AvgTPS = metric(func(r *Result) float64 { return r.AvgTPS })
+	report.MaxTPS = metric(func(r *Result) float64 { return r.MaxTPS })
+	report.SteadyTPS = metric(func(r *Result) float64 { return r.SteadyTPS })
+	report.WindowTPS = metric(func(r *Result) float64 { return r.PeakWindowTPS })
+	report.AvgLatency = metric(func(r *Result) float64 { return r.AvgLatency })
+	report.LatencyP50 = metric(func(r *Result) float64 { return r.LatencyP50 })
+	report.LatencyP95 = metric(func(r *Result) float64 { return r.LatencyP95 })
+	report.LatencyP99 = metric(func(r *Result) float64 { return r.LatencyP99 })
+	report.GasPerSec = metric(func(r *Result) float64 { return r.GasPerSecond })
+	report.BlockGas = metric(func(r *Result) float64 { return r.BlockGasPerSecond })
+	report.Fullness = metric(func(r *Result) float64 { return r.BlockFullness })
+
+	log.Printf("avg tps = %.2f ± %.2f (95%% CI %.2f..%.2f)\n", report.AvgTPS.Mean, report.AvgTPS.StdDev, report.AvgTPS.CILow, report.AvgTPS.CIHigh)
+	log.Printf("steady state tps = %.2f ± %.2f (95%% CI %.2f..%.2f)\n", report.SteadyTPS.Mean, report.SteadyTPS.StdDev, report.SteadyTPS.CILow, report.SteadyTPS.CIHigh)
+	log.Printf("max tps = %.2f ± %.2f (95%% CI %.2f..%.2f)\n", report.MaxTPS.Mean, report.MaxTPS.StdDev, report.MaxTPS.CILow, report.MaxTPS.CIHigh)
+	log.Printf("p95 latency = %.2f ± %.2f\n", report.LatencyP95.Mean, report.LatencyP95.StdDev)
+
+	filename := fmt.Sprintf("%v.%v.%v.%v.repeat.json", config.Network, report.Started.Format("20060102_150405"), opts.Trials, report.Workload)
+	StoreReport(report, filename)
+	return report
+}
+
+// pause waits for the cooldown and resets the chain before the next trial.
+func (opts RepeatOptions) pause() {
+	if opts.Cooldown > 0 {
+		log.Printf("cooldown %v\n", opts.Cooldown)
+		time.Sleep(opts.Cooldown)
+	}
+	if opts.Reset != "" {
+		resetChain(opts.Reset)
+	}
+}
+
+func resetChain(command string) {
+	log.Println("reset:", command)
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		log.Fatalf("Failed to reset chain: %v", err)
+	}
+	// the reset may have deployed the contracts again, the flags stay in effect
+	config.ReloadAddresses("config/config.yml")
+}
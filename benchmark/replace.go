@@ -0,0 +1,116 @@
+package benchmark
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"decipher.com/tps/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ReplacementPolicy resends a transaction that is not mined After its
+// submission with the same nonce and a fee raised by Bump percent, at most
+// MaxAttempts times. A zero After disables replacements.
+type ReplacementPolicy struct {
+	After       time.Duration
+	Bump        int
+	MaxAttempts int
+}
+
+func replacementPolicy() ReplacementPolicy {
+	return ReplacementPolicy{After: config.ReplaceAfter, Bump: config.FeeBump, MaxAttempts: config.MaxReplacements}
+}
+
+// replacements counts the replacement transactions sent and the transactions
+// mined through one of their replacements.
+type replacements struct {
+	sent  atomic.Int64
+	mined atomic.Int64
+}
+
+func (r *replacements) record(result *Result) {
+	result.Replacements = int(r.sent.Load())
+	result.ReplacedMined = int(r.mined.Load())
+	if result.Replacements > 0 {
+		log.Printf("replacements: %v sent, %v transactions mined by a replacement\n", result.Replacements, result.ReplacedMined)
+	}
+}
+
+// bumpFee returns a copy of tx with its gas price, or tip and fee cap, raised
+// by percent and at least by one wei.
+func bumpFee(tx *types.Transaction, percent int) types.TxData {
+	bump := func(v *big.Int) *big.Int {
+		bumped := new(big.Int).Mul(v, big.NewInt(int64(100+percent)))
+		bumped.Div(bumped, big.NewInt(100))
+		if bumped.Cmp(v) <= 0 {
+			bumped.Add(v, common.Big1)
+		}
+		return bumped
+	}
+	switch tx.Type() {
+	case types.DynamicFeeTxType:
+		return &types.DynamicFeeTx{ChainID: tx.ChainId(), Nonce: tx.Nonce(), GasTipCap: bump(tx.GasTipCap()), GasFeeCap: bump(tx.GasFeeCap()), Gas: tx.Gas(), To: tx.To(), Value: tx.Value(), Data: tx.Data(), AccessList: tx.AccessList()}
+	case types.AccessListTxType:
+		return &types.AccessListTx{ChainID: tx.ChainId(), Nonce: tx.Nonce(), GasPrice: bump(tx.GasPrice()), Gas: tx.Gas(), To: tx.To(), Value: tx.Value(), Data: tx.Data(), AccessList: tx.AccessList()}
+	default:
+		return &types.LegacyTx{Nonce: tx.Nonce(), GasPrice: bump(tx.GasPrice()), Gas: tx.Gas(), To: tx.To(), Value: tx.Value(), Data: tx.Data()}
+	}
+}
+
+// waitReplacing waits until tx or one of its replacements is mined. Each time
+// the latest one is not mined within policy.After, a replacement with a bumped
+// fee is signed by key, the key of the sender, and sent.
+func waitReplacing(ctx context.Context, client receiptReader, errs contractErrors, send func(context.Context, *types.Transaction) error, tx *types.Transaction, key *ecdsa.PrivateKey, policy ReplacementPolicy, counter *replacements) (*types.Receipt, error) {
+	if policy.After <= 0 || policy.MaxAttempts <= 0 {
+		return waitMined(ctx, client, errs, tx)
+	}
+	signer := types.LatestSignerForChainID(config.ChainID)
+	txs := []*types.Transaction{tx}
+	for attempt := 0; ; attempt++ {
+		waitCtx, cancel := ctx, context.CancelFunc(func() {})
+		if attempt < policy.MaxAttempts {
+			waitCtx, cancel = context.WithTimeout(ctx, policy.After)
+		}
+		receipt, err := waitMinedAny(waitCtx, client, errs, txs)
+		cancel()
+		if err != context.DeadlineExceeded || ctx.Err() != nil {
+			if receipt != nil && receipt.TxHash != tx.Hash() {
+				counter.mined.Add(1)
+			}
+			return receipt, err
+		}
+
+		replacement, err := replace(txs[len(txs)-1], signer, policy.Bump, key)
+		if err != nil {
+			log.Printf("replacement of %v: %v\n", tx.Hash(), err)
+			policy.MaxAttempts = attempt
+			continue
+		}
+		if err = send(ctx, replacement); err != nil {
+			// nonce too low: one of the sent transactions was mined meanwhile
+			log.Printf("replacement of %v: %v\n", tx.Hash(), err)
+			continue
+		}
+		counter.sent.Add(1)
+		txs = append(txs, replacement)
+	}
+}
+
+// replace returns tx with its fee bumped by percent, signed again by key.
+func replace(tx *types.Transaction, signer types.Signer, percent int, key *ecdsa.PrivateKey) (*types.Transaction, error) {
+	sender, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || crypto.PubkeyToAddress(key.PublicKey) != sender {
+		return nil, fmt.Errorf("no key to replace the transaction of %v", sender)
+	}
+	return types.SignNewTx(key, signer, bumpFee(tx, percent))
+}
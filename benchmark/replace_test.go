@@ -0,0 +1,52 @@
+package benchmark
+
+import (
+	"math/big"
+	"testing"
+
+	"decipher.com/tps/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestBumpFee(t *testing.T) {
+	to := common.Address{1}
+	legacy := types.NewTx(&types.LegacyTx{Nonce: 7, GasPrice: big.NewInt(1000), Gas: 21000, To: &to, Value: big.NewInt(5)})
+	bumped := types.NewTx(bumpFee(legacy, 10))
+	if bumped.GasPrice().Int64() != 1100 || bumped.Nonce() != 7 || bumped.Gas() != 21000 || *bumped.To() != to || bumped.Value().Int64() != 5 {
+		t.Errorf("legacy = %v %v %v", bumped.GasPrice(), bumped.Nonce(), bumped.Gas())
+	}
+
+	dynamic := types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 3, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(200), Gas: 50000, Data: []byte{1, 2}})
+	bumped = types.NewTx(bumpFee(dynamic, 12))
+	if bumped.Type() != types.DynamicFeeTxType || bumped.GasTipCap().Int64() != 2 || bumped.GasFeeCap().Int64() != 224 || len(bumped.Data()) != 2 {
+		t.Errorf("dynamic = %v %v", bumped.GasTipCap(), bumped.GasFeeCap())
+	}
+}
+
+func TestReplace(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := types.LatestSignerForChainID(config.ChainID)
+	tx, err := types.SignNewTx(key, signer, &types.LegacyTx{Nonce: 9, GasPrice: big.NewInt(100), Gas: 21000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	replacement, err := replace(tx, signer, 20, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, _ := types.Sender(signer, replacement)
+	if sender != crypto.PubkeyToAddress(key.PublicKey) || replacement.Nonce() != 9 || replacement.GasPrice().Int64() != 120 {
+		t.Errorf("replacement from %v, nonce %v, gas price %v", sender, replacement.Nonce(), replacement.GasPrice())
+	}
+
+	other, _ := crypto.GenerateKey()
+	tx, _ = types.SignNewTx(other, signer, &types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(100), Gas: 21000})
+	if _, err = replace(tx, signer, 20, key); err == nil {
+		t.Error("replaced the transaction of another sender")
+	}
+	if _, err = replace(tx, signer, 20, nil); err == nil {
+		t.Error("replaced a transaction without a key")
+	}
+}
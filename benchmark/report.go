@@ -0,0 +1,95 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Result summarizes a single benchmark run. Unconfirmed counts the
+// transactions neither confirmed nor failed, lost or still pending when the
+// drain phase timed out (DrainTimeout). AvgTPS is the whole-run TPS of all
+// transactions in the observed blocks, SteadyTPS excludes the configured
+// warm-up and cool-down and PeakWindowTPS is the highest TPS over the rolling
+// window. OperationTPS only counts the successful workload operations and is
+// filled when Runner.CountMode is "events". GasPerSecond is the gas used by
+// the workload's mined transactions over the duration, BlockGasPerSecond the
+// gas of all transactions in the observed blocks. BlockFullness is the
+// percentage of the blocks' gas limit used, the sizes are in bytes. Transport
+// and Connections are the submit transports and the connections per endpoint.
+// Replacements counts the fee-bumped resubmissions of stuck transactions and
+// ReplacedMined the transactions mined through one of them. Orphaned counts
+// the transactions of reorged blocks that were not mined again, Reincluded
+// those that were.
+type Result struct {
+	Workload      string  `json:"workload"`
+	Contention    int     `json:"contention"`
+	Total         int     `json:"total"`
+	Confirmed     int     `json:"confirmed"`
+	Failed        int     `json:"failed"`
+	Unconfirmed   int     `json:"unconfirmed"`
+	DrainTimeout  bool    `json:"drain_timeout"`
+	Duration      float64 `json:"duration"`
+	Transactions  int     `json:"transactions"`
+	Operations    int     `json:"operations"`
+	AvgTPS        float64 `json:"avg_tps"`
+	OperationTPS  float64 `json:"operation_tps"`
+	MaxTPS        float64 `json:"max_tps"`
+	SteadyTPS     float64 `json:"steady_tps"`
+	SteadyBlocks  int     `json:"steady_blocks"`
+	PeakWindowTPS float64 `json:"peak_window_tps"`
+	AvgLatency    float64 `json:"avg_latency"`
+	LatencyP50    float64 `json:"latency_p50"`
+	LatencyP95    float64 `json:"latency_p95"`
+	LatencyP99    float64 `json:"latency_p99"`
+	MaxLatency    float64 `json:"max_latency"`
+	GasUsed       uint64  `json:"gas_used"`
+	GasPerSecond  float64 `json:"gas_per_second"`
+	Replacements  int     `json:"replacements"`
+	ReplacedMined int     `json:"replaced_mined"`
+	Transport     string  `json:"transport"`
+	Connections   int     `json:"connections"`
+
+	BlockGasUsed      uint64  `json:"block_gas_used"`
+	BlockGasPerSecond float64 `json:"block_gas_per_second"`
+	BlockFullness     float64 `json:"block_fullness"`
+	AvgBlockSize      float64 `json:"avg_block_size"`
+	AvgTxSize         float64 `json:"avg_tx_size"`
+
+	Reorgs     []ReorgEvent `json:"reorgs,omitempty"`
+	Orphaned   int          `json:"orphaned"`
+	Reincluded int          `json:"reincluded"`
+	Uncles     int          `json:"uncles"`
+
+	Fees         *FeeSummary                       `json:"fees,omitempty"`
+	Propagation  *PropagationReport                `json:"propagation,omitempty"`
+	Endpoints    []EndpointStats                   `json:"endpoints,omitempty"`
+	Mempool      *MempoolReport                    `json:"mempool,omitempty"`
+	Failures     map[ErrorCategory]*FailureSummary `json:"failures,omitempty"`
+	Verification *Verification                     `json:"verification,omitempty"`
+
+	BlockFile string `json:"block_file"`
+}
+
+// StoreReport writes v as indented JSON into the result directory.
+func StoreReport(v any, filename string) {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Println("report:", err)
+		return
+	}
+	err = os.WriteFile(filepath.Join(".", "result", filename), content, 0644)
+	if err != nil {
+		log.Println("report:", err)
+		return
+	}
+	log.Println("report saved as", filename)
+}
+
+// StoreResult writes the result of a single run next to its block file, as
+// <block file>.result.json.
+func StoreResult(result *Result) {
+	StoreReport(result, strings.TrimSuffix(result.BlockFile, ".txt")+".result.json")
+}
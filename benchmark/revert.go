@@ -0,0 +1,117 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"decipher.com/tps/abi"
+	"github.com/ethereum/go-ethereum"
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RevertError is returned for a mined transaction with a failed receipt.
+type RevertError struct {
+	Hash   common.Hash
+	Reason string
+}
+
+func (e *RevertError) Error() string {
+	return fmt.Sprintf("execution reverted: %v (%v)", e.Reason, e.Hash)
+}
+
+// contractErrors are the custom errors a run decodes by their selector.
+type contractErrors map[[4]byte]ethabi.Error
+
+// bundledErrors are the custom errors declared in the bundled contract ABIs.
+// Every run starts from them and never changes them.
+var bundledErrors = loadContractErrors(abi.ERC20MetaData, abi.ERC721MetaData, abi.ERC1155MetaData)
+
+func loadContractErrors(metadata ...*bind.MetaData) contractErrors {
+	errs := make(contractErrors)
+	for _, m := range metadata {
+		parsed, err := m.GetAbi()
+		if err != nil {
+			continue
+		}
+		for _, e := range parsed.Errors {
+			errs[[4]byte(e.ID[:4])] = e
+		}
+	}
+	return errs
+}
+
+// with returns a copy of c that decodes the custom errors of parsed as well.
+func (c contractErrors) with(parsed ethabi.ABI) contractErrors {
+	errs := make(contractErrors, len(c)+len(parsed.Errors))
+	for id, e := range c {
+		errs[id] = e
+	}
+	for _, e := range parsed.Errors {
+		errs[[4]byte(e.ID[:4])] = e
+	}
+	return errs
+}
+
+// revertReason replays the failed transaction with eth_call at its inclusion
+// block and decodes the revert data with errs.
+func revertReason(ctx context.Context, client ethereum.ContractCaller, errs contractErrors, tx *types.Transaction, receipt *types.Receipt) string {
+	if receipt.GasUsed == tx.Gas() {
+		return "out of gas"
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return "unknown"
+	}
+	msg := ethereum.CallMsg{
+		From:     from,
+		To:       tx.To(),
+		Gas:      tx.Gas(),
+		GasPrice: tx.GasPrice(),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	}
+	_, err = client.CallContract(ctx, msg, receipt.BlockNumber)
+	if err == nil {
+		return "unknown (call succeeded on replay)"
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if raw, decodeErr := hexutil.Decode(data); decodeErr == nil && len(raw) > 0 {
+				return errs.decode(raw)
+			}
+		}
+	}
+	return strings.TrimPrefix(err.Error(), "execution reverted: ")
+}
+
+// decode decodes Error(string), Panic(uint256) and the custom errors of c.
+func (c contractErrors) decode(data []byte) string {
+	if reason, err := ethabi.UnpackRevert(data); err == nil {
+		return reason
+	}
+	if len(data) >= 4 {
+		if e, ok := c[[4]byte(data[:4])]; ok {
+			values, err := e.Unpack(data)
+			if err != nil {
+				return e.Sig
+			}
+			args := make([]string, 0)
+			if list, ok := values.([]interface{}); ok {
+				for _, v := range list {
+					args = append(args, fmt.Sprint(v))
+				}
+			}
+			return fmt.Sprintf("%v(%v)", e.Name, strings.Join(args, ", "))
+		}
+	}
+	return hexutil.Encode(data)
+}
@@ -0,0 +1,300 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"decipher.com/tps/abi"
+	"decipher.com/tps/config"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// RPCMethods are the read methods rpcbench can call. The eth_call methods go
+// through the Caller bindings of the deployed contracts.
+var RPCMethods = []string{"balanceOf", "ownerOf", "balanceOfBatch", "eth_getBalance", "eth_getLogs", "eth_getBlockByNumber", "eth_estimateGas"}
+
+// RPCBenchOptions configures the read load. Rate is the number of requests per
+// second over all methods, which are called in turn. LogRange is the number of
+// blocks queried by eth_getLogs and the depth eth_getBlockByNumber reads from.
+// TokenRange bounds the ERC721 token ids passed to ownerOf. Write names a
+// benchmark workload run at the same time.
+type RPCBenchOptions struct {
+	Methods     []string `yaml:"methods"`
+	Rate        int      `yaml:"rate"`
+	Duration    int      `yaml:"duration"`
+	Concurrency int      `yaml:"concurrency"`
+	LogRange    int      `yaml:"logRange"`
+	TokenRange  int      `yaml:"tokenRange"`
+	Write       string   `yaml:"write"`
+}
+
+func (opts RPCBenchOptions) Validate() error {
+	for _, method := range opts.Methods {
+		if !slices.Contains(RPCMethods, method) {
+			return fmt.Errorf("unknown method %q, expected one of %v", method, strings.Join(RPCMethods, ", "))
+		}
+	}
+	if opts.Rate <= 0 || opts.Duration <= 0 || opts.Concurrency <= 0 {
+		return fmt.Errorf("rate, duration and concurrency must be positive")
+	}
+	if opts.Write != "" {
+		if _, ok := Lookup(opts.Write); !ok {
+			return fmt.Errorf("unknown write workload %q", opts.Write)
+		}
+	}
+	return nil
+}
+
+// MethodStats are the throughput, error rate and latencies (seconds) of one method.
+type MethodStats struct {
+	Method     string   `json:"method"`
+	Requests   int      `json:"requests"`
+	Errors     int      `json:"errors"`
+	QPS        float64  `json:"qps"`
+	ErrorRate  float64  `json:"error_rate"`
+	AvgLatency float64  `json:"avg_latency"`
+	LatencyP50 float64  `json:"latency_p50"`
+	LatencyP95 float64  `json:"latency_p95"`
+	LatencyP99 float64  `json:"latency_p99"`
+	MaxLatency float64  `json:"max_latency"`
+	Samples    []string `json:"samples,omitempty"`
+}
+
+type RPCReport struct {
+	Network  string        `json:"network"`
+	Started  time.Time     `json:"started"`
+	Duration float64       `json:"duration"`
+	Rate     int           `json:"rate"`
+	QPS      float64       `json:"qps"`
+	Methods  []MethodStats `json:"methods"`
+	Write    *Result       `json:"write,omitempty"`
+}
+
+// latencyRecorder collects the latencies and errors of one method or endpoint.
+type latencyRecorder struct {
+	mutex     sync.Mutex
+	latencies []float64
+	errors    int
+	samples   []string
+}
+
+func (m *latencyRecorder) record(elapsed time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if err != nil {
+		m.errors++
+		if len(m.samples) < 3 {
+			m.samples = append(m.samples, err.Error())
+		}
+		return
+	}
+	m.latencies = append(m.latencies, elapsed.Seconds())
+}
+
+func (m *latencyRecorder) stats(method string, duration float64) MethodStats {
+	s := MethodStats{Method: method, Requests: len(m.latencies) + m.errors, Errors: m.errors, Samples: m.samples}
+	if duration > 0 {
+		s.QPS = float64(len(m.latencies)) / duration
+	}
+	if s.Requests > 0 {
+		s.ErrorRate = float64(m.errors) / float64(s.Requests)
+	}
+	if len(m.latencies) > 0 {
+		total := 0.0
+		for _, latency := range m.latencies {
+			total += latency
+		}
+		s.AvgLatency = total / float64(len(m.latencies))
+	}
+	s.LatencyP50 = percentile(m.latencies, 50)
+	s.LatencyP95 = percentile(m.latencies, 95)
+	s.LatencyP99 = percentile(m.latencies, 99)
+	s.MaxLatency = percentile(m.latencies, 100)
+	return s
+}
+
+// RPCBench calls the read methods at opts.Rate requests per second for
+// opts.Duration seconds with opts.Concurrency workers. A request that finds
+// every worker busy waits, so the achieved QPS shows when the node falls behind,
+// and the wait counts in its latency.
+func RPCBench(opts RPCBenchOptions) *RPCReport {
+	if len(opts.Methods) == 0 {
+		opts.Methods = RPCMethods
+	}
+	if err := opts.Validate(); err != nil {
+		log.Fatalf("Invalid rpcbench options: %v", err)
+	}
+	client, err := ethclient.Dial(config.Host1)
+	if err != nil {
+		log.Fatalf("client: %v", err)
+	}
+	defer client.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	call := newReadCalls(ctx, client, opts)
+
+	var write chan *Result
+	if definition, ok := Lookup(opts.Write); ok {
+		write = make(chan *Result, 1)
+		go func() {
+			write <- definition.Run(NewRunner(config.Total), ConstantRate(config.Rate))
+		}()
+	}
+
+	report := &RPCReport{Network: config.Network, Started: time.Now(), Rate: opts.Rate}
+	recorders := generateReads(report.Started, opts, call)
+	report.Duration = time.Since(report.Started).Seconds()
+
+	succeeded := 0
+	for _, method := range opts.Methods {
+		stats := recorders[method].stats(method, report.Duration)
+		succeeded += stats.Requests - stats.Errors
+		report.Methods = append(report.Methods, stats)
+		log.Printf("%-20v qps %8.2f  errors %5.2f%%  p50 %.4fs  p95 %.4fs  p99 %.4fs\n", method, stats.QPS, 100*stats.ErrorRate, stats.LatencyP50, stats.LatencyP95, stats.LatencyP99)
+	}
+	report.QPS = float64(succeeded) / report.Duration
+	log.Printf("total qps = %.2f\n", report.QPS)
+
+	if write != nil {
+		log.Println("waiting for the write workload")
+		report.Write = <-write
+	}
+
+	filename := fmt.Sprintf("%v.%v.%v.rpcbench.json", config.Network, report.Started.Format("20060102_150405"), opts.Rate)
+	StoreReport(report, filename)
+	return report
+}
+
+// readJob is the i-th request and the time it is scheduled to be sent.
+type readJob struct {
+	i         int
+	scheduled time.Time
+}
+
+// generateReads calls the methods of opts in turn, spacing the requests evenly
+// at opts.Rate per second from start. The latency of a request is measured from
+// its scheduled time, so the time it waits for a busy worker is not hidden.
+func generateReads(start time.Time, opts RPCBenchOptions, call func(method string, i int) error) map[string]*latencyRecorder {
+	recorders := make(map[string]*latencyRecorder)
+	for _, method := range opts.Methods {
+		recorders[method] = &latencyRecorder{}
+	}
+	jobs := make(chan readJob, opts.Rate)
+	var wait sync.WaitGroup
+	for w := 0; w < opts.Concurrency; w++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for job := range jobs {
+				method := opts.Methods[job.i%len(opts.Methods)]
+				err := call(method, job.i)
+				recorders[method].record(time.Since(job.scheduled), err)
+			}
+		}()
+	}
+
+	for i := 0; i < opts.Rate*opts.Duration; i++ {
+		scheduled := start.Add(time.Duration(i) * time.Second / time.Duration(opts.Rate))
+		time.Sleep(time.Until(scheduled))
+		jobs <- readJob{i, scheduled}
+	}
+	close(jobs)
+	wait.Wait()
+	return recorders
+}
+
+// newReadCalls returns a function that performs the i-th request of method.
+func newReadCalls(ctx context.Context, client *ethclient.Client, opts RPCBenchOptions) func(method string, i int) error {
+	erc20, _ := abi.NewERC20Caller(config.ERC20ADDRESS, client)
+	erc721, _ := abi.NewERC721Caller(config.ERC721ADDRESS, client)
+	erc1155, _ := abi.NewERC1155Caller(config.ERC1155ADDRESS, client)
+	erc20ABI, _ := abi.ERC20MetaData.GetAbi()
+
+	if len(config.PrivateKeyHex) == 0 {
+		log.Fatal("No accounts loaded")
+	}
+	accounts := make([]common.Address, 0, min(len(config.PrivateKeyHex), 1000))
+	for _, key := range config.PrivateKeyHex[:cap(accounts)] {
+		_, address := GetKeyAndAddress(key)
+		accounts = append(accounts, address)
+	}
+	account := func(i int) common.Address {
+		return accounts[i%len(accounts)]
+	}
+	tokenRange := max(opts.TokenRange, 1)
+	logRange := uint64(max(opts.LogRange, 1))
+	width := min(config.BatchWidth, len(accounts))
+
+	// the head is refreshed in the background, so getLogs and getBlockByNumber
+	// query recent blocks without an extra request
+	var head atomic.Uint64
+	number, err := client.BlockNumber(ctx)
+	if err != nil {
+		log.Fatalf("Failed to get block number: %v", err)
+	}
+	head.Store(number)
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if number, err := client.BlockNumber(ctx); err == nil {
+					head.Store(number)
+				}
+			}
+		}
+	}()
+
+	return func(method string, i int) error {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		callOpts := &bind.CallOpts{Context: ctx}
+		var err error
+		switch method {
+		case "balanceOf":
+			_, err = erc20.BalanceOf(callOpts, account(i))
+		case "ownerOf":
+			_, err = erc721.OwnerOf(callOpts, big.NewInt(int64(i%tokenRange)))
+		case "balanceOfBatch":
+			ids := make([]*big.Int, width)
+			holders := make([]common.Address, width)
+			for k := range ids {
+				ids[k] = big.NewInt(int64((i+k)%tokenRange + 1))
+				holders[k] = account(i + k)
+			}
+			_, err = erc1155.BalanceOfBatch(callOpts, holders, ids)
+		case "eth_getBalance":
+			_, err = client.BalanceAt(ctx, account(i), nil)
+		case "eth_getLogs":
+			to := head.Load()
+			from := to - min(to, logRange-1)
+			_, err = client.FilterLogs(ctx, ethereum.FilterQuery{
+				FromBlock: new(big.Int).SetUint64(from),
+				ToBlock:   new(big.Int).SetUint64(to),
+				Addresses: []common.Address{config.ERC20ADDRESS, config.ERC721ADDRESS, config.ERC1155ADDRESS},
+			})
+		case "eth_getBlockByNumber":
+			to := head.Load()
+			number := to - uint64(rand.Int63n(int64(min(to+1, logRange))))
+			_, err = client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+		case "eth_estimateGas":
+			data, _ := erc20ABI.Pack("transfer", account(i+1), big.NewInt(1))
+			_, err = client.EstimateGas(ctx, ethereum.CallMsg{From: accounts[0], To: &config.ERC20ADDRESS, Data: data})
+		}
+		return err
+	}
+}
@@ -0,0 +1,67 @@
+package benchmark
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLatencyRecorder(t *testing.T) {
+	m := &latencyRecorder{}
+	for i := 1; i <= 100; i++ {
+		m.record(time.Duration(i)*time.Millisecond, nil)
+	}
+	for i := 0; i < 25; i++ {
+		m.record(time.Second, errors.New("execution reverted: ERC721: invalid token ID"))
+	}
+	s := m.stats("ownerOf", 10)
+	if s.Requests != 125 || s.Errors != 25 || s.ErrorRate != 0.2 {
+		t.Errorf("requests = %v, errors = %v, error rate = %v", s.Requests, s.Errors, s.ErrorRate)
+	}
+	if s.QPS != 10 {
+		t.Errorf("qps = %v, want 10", s.QPS)
+	}
+	if s.LatencyP50 != 0.05 || s.LatencyP99 != 0.099 || s.MaxLatency != 0.1 {
+		t.Errorf("latencies = %v, %v, %v", s.LatencyP50, s.LatencyP99, s.MaxLatency)
+	}
+	if len(s.Samples) != 3 {
+		t.Errorf("samples = %v", s.Samples)
+	}
+}
+
+func TestRPCBenchOptions(t *testing.T) {
+	opts := RPCBenchOptions{Methods: []string{"eth_getLogs", "ownerOf"}, Rate: 10, Duration: 1, Concurrency: 1}
+	if err := opts.Validate(); err != nil {
+		t.Error(err)
+	}
+	opts.Methods = []string{"eth_getProof"}
+	if err := opts.Validate(); err == nil {
+		t.Error("expected unknown method")
+	}
+	opts.Methods, opts.Write = nil, "erc20burn"
+	if err := opts.Validate(); err == nil {
+		t.Error("expected unknown write workload")
+	}
+}
+
+func TestGenerateReadsQueueTime(t *testing.T) {
+	// one worker serves a request every 150ms while they are scheduled every
+	// 100ms, so the last ones wait for the worker
+	opts := RPCBenchOptions{Methods: []string{"balanceOf", "ownerOf"}, Rate: 10, Duration: 1, Concurrency: 1}
+	recorders := generateReads(time.Now(), opts, func(method string, i int) error {
+		time.Sleep(150 * time.Millisecond)
+		return nil
+	})
+	for _, method := range opts.Methods {
+		s := recorders[method].stats(method, 1)
+		if s.Requests != 5 {
+			t.Errorf("%v: %v requests, want 5", method, s.Requests)
+		}
+		if s.LatencyP50 < 0.15 {
+			t.Errorf("%v: p50 latency %.3fs below the call time", method, s.LatencyP50)
+		}
+	}
+	if s := recorders["ownerOf"].stats("ownerOf", 1); s.MaxLatency < 0.5 {
+		t.Errorf("max latency %.3fs misses the queue time", s.MaxLatency)
+	}
+}
@@ -0,0 +1,223 @@
+package benchmark
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log"
+	"time"
+
+	"decipher.com/tps/config"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Workload is a benchmark run by a Runner. Setup prepares the run before the
+// block watcher starts, e.g. deploys a contract, funds or picks the senders
+// with SendFrom, or snapshots the state to verify. Transaction signs and sends
+// one transaction of the run with tx.Opts, or with tx.Sender and tx.Nonce.
+// The built-in workloads keep the state of one run at a time.
+type Workload interface {
+	// Name is the operation type the run is counted and reported as.
+	Name() string
+	Setup(ctx context.Context, bc *BenchmarkContext) error
+	Transaction(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error)
+}
+
+// Verifier is a Workload that checks the on-chain state after the run when
+// Runner.Verify is set. Verify may return nil when there is nothing to check.
+type Verifier interface {
+	Verify(ctx context.Context, bc *BenchmarkContext) *Verification
+}
+
+// Teardowner is a Workload that cleans up after the run, e.g. returns the
+// funds of its senders.
+type Teardowner interface {
+	Teardown(ctx context.Context, bc *BenchmarkContext) error
+}
+
+// Runner runs workloads with its own endpoints and options. Every run owns its
+// block watcher, counters and result, so the runs of Runners with different
+// settings may overlap as long as their senders differ. The chain id, gas
+// limit, network name and the windows of the block statistics stay the ones of
+// config.
+type Runner struct {
+	// Key signs the transactions unless the workload's setup picks another sender.
+	Key *ecdsa.PrivateKey
+	// Accounts are the accounts of the run: the recipients of the transactions
+	// and the senders a workload picks, e.g. the spender of transferFrom.
+	Accounts []*ecdsa.PrivateKey
+	// Total is the number of transactions of a run.
+	Total int
+	// Endpoints are the nodes submitted to and observed, the first observe
+	// endpoint counts the blocks. Strategy picks the submit endpoint of a
+	// transaction, with Connections clients per endpoint.
+	Endpoints   []config.Endpoint
+	Strategy    string
+	Connections int
+	// CountMode "events" counts the successful operations of the workload
+	// instead of the transactions of the blocks.
+	CountMode string
+	// Contention is the percentage of transactions routed to the hot state.
+	Contention int
+	// Verify checks the on-chain state of a Verifier after the run.
+	Verify bool
+	// Propagation measures the propagation of the transactions to every node.
+	Propagation bool
+	// MempoolInterval samples the pool of the first submit endpoint when
+	// positive, MempoolContent follows the benchmark transactions in it.
+	MempoolInterval time.Duration
+	MempoolContent  bool
+	// Replacement resends the transactions that are not mined in time.
+	Replacement ReplacementPolicy
+	// DrainTimeout bounds the wait for the sent transactions after sending.
+	DrainTimeout time.Duration
+}
+
+// NewRunner returns a Runner sending total transactions per run from the first
+// account, with the accounts, endpoints and options of config.
+func NewRunner(total int) *Runner {
+	return &Runner{
+		Key:             config.PrivateKey[0],
+		Accounts:        config.PrivateKey,
+		Total:           total,
+		Endpoints:       config.Endpoints,
+		Strategy:        config.Strategy,
+		Connections:     config.Connections,
+		CountMode:       config.CountMode,
+		Contention:      config.Contention,
+		Verify:          config.Verify,
+		Propagation:     config.Propagation,
+		MempoolInterval: config.MempoolInterval,
+		MempoolContent:  config.MempoolContent,
+		Replacement:     replacementPolicy(),
+		DrainTimeout:    config.DrainTimeout,
+	}
+}
+
+// validate checks the settings of a run of profile.
+func (r *Runner) validate(profile LoadProfile) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+	if r.Total <= 0 {
+		return fmt.Errorf("total must be positive, got %d", r.Total)
+	}
+	if r.Key == nil {
+		return fmt.Errorf("no sending key")
+	}
+	if r.Contention < 0 || r.Contention > 100 {
+		return fmt.Errorf("contention %d is not between 0 and 100", r.Contention)
+	}
+	if r.DrainTimeout <= 0 {
+		return fmt.Errorf("drain timeout must be positive, got %v", r.DrainTimeout)
+	}
+	if _, ok := r.endpoint(config.Endpoint.Submits); !ok {
+		return fmt.Errorf("no submit endpoint")
+	}
+	if _, ok := r.endpoint(config.Endpoint.Observes); !ok {
+		return fmt.Errorf("no observe endpoint")
+	}
+	return validateStrategy(r.Strategy)
+}
+
+// endpoint returns the URL of the first endpoint with role.
+func (r *Runner) endpoint(role func(config.Endpoint) bool) (string, bool) {
+	for _, endpoint := range r.Endpoints {
+		if role(endpoint) {
+			return endpoint.URL, true
+		}
+	}
+	return "", false
+}
+
+// Run sets up w, sends its transactions at the rate of profile, drains and
+// finalizes the run, verifies it with Verify and tears it down. Cancelling ctx
+// ends the send and drain phases early. A failed teardown returns the result
+// with the error.
+func (r *Runner) Run(ctx context.Context, w Workload, profile LoadProfile) (*Result, error) {
+	if err := r.validate(profile); err != nil {
+		return nil, err
+	}
+	submit, _ := r.endpoint(config.Endpoint.Submits)
+	client, err := ethclient.DialContext(ctx, submit)
+	if err != nil {
+		return nil, fmt.Errorf("client: %w", err)
+	}
+	defer client.Close()
+
+	bc, err := newBenchmarkContext(ctx, client, *r, profile)
+	if err != nil {
+		return nil, err
+	}
+	// verify and teardown still read and send over the pooled connections
+	defer bc.Backend.close()
+	if err = w.Setup(ctx, bc); err != nil {
+		bc.cancel()
+		return nil, fmt.Errorf("%v setup: %w", w.Name(), err)
+	}
+	bc.start(w.Name())
+	result := bc.Benchmark(func(tx Tx) (*types.Transaction, error) {
+		return w.Transaction(bc.Ctx, bc, tx)
+	})
+	bc.watcher.wait()
+	if verifier, ok := w.(Verifier); ok && r.Verify {
+		result.Verification = verifier.Verify(ctx, bc)
+	}
+	if teardowner, ok := w.(Teardowner); ok {
+		if err = teardowner.Teardown(ctx, bc); err != nil {
+			return result, fmt.Errorf("%v teardown: %w", w.Name(), err)
+		}
+	}
+	return result, nil
+}
+
+// run runs w with r and exits on errors, as the commands do.
+func run(r *Runner, profile LoadProfile, w Workload) *Result {
+	result, err := r.Run(context.Background(), w, profile)
+	if err != nil && result == nil {
+		log.Fatalf("%v: %v", w.Name(), err)
+	}
+	if err != nil {
+		log.Println(err)
+	}
+	return result
+}
+
+// workload implements Workload with the closures of a built-in benchmark.
+type workload struct {
+	name        string
+	setup       func(ctx context.Context, bc *BenchmarkContext) error
+	transaction func(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error)
+	verify      func(ctx context.Context, bc *BenchmarkContext) *Verification
+	teardown    func(ctx context.Context, bc *BenchmarkContext) error
+}
+
+func (w *workload) Name() string {
+	return w.name
+}
+
+func (w *workload) Setup(ctx context.Context, bc *BenchmarkContext) error {
+	if w.setup == nil {
+		return nil
+	}
+	return w.setup(ctx, bc)
+}
+
+func (w *workload) Transaction(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error) {
+	return w.transaction(ctx, bc, tx)
+}
+
+func (w *workload) Verify(ctx context.Context, bc *BenchmarkContext) *Verification {
+	if w.verify == nil {
+		return nil
+	}
+	return w.verify(ctx, bc)
+}
+
+func (w *workload) Teardown(ctx context.Context, bc *BenchmarkContext) error {
+	if w.teardown == nil {
+		return nil
+	}
+	return w.teardown(ctx, bc)
+}
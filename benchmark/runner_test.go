@@ -0,0 +1,198 @@
+package benchmark
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"decipher.com/tps/abi"
+	"decipher.com/tps/config"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestRunnerRejectsInvalidRuns(t *testing.T) {
+	w := NativeTransferWorkload()
+	if _, err := (&Runner{Total: 10}).Run(context.Background(), w, ConstantRate(0)); err == nil {
+		t.Error("zero rate accepted")
+	}
+	if _, err := (&Runner{}).Run(context.Background(), w, ConstantRate(10)); err == nil {
+		t.Error("zero total accepted")
+	}
+
+	key, _ := crypto.GenerateKey()
+	valid := func() *Runner {
+		return &Runner{Key: key, Total: 10, Strategy: "round-robin", DrainTimeout: time.Second, Endpoints: []config.Endpoint{{URL: "ws://127.0.0.1:1", Role: "both"}}}
+	}
+	if err := valid().validate(ConstantRate(10)); err != nil {
+		t.Fatalf("valid runner rejected: %v", err)
+	}
+	for name, invalidate := range map[string]func(r *Runner){
+		"no key":           func(r *Runner) { r.Key = nil },
+		"contention 101":   func(r *Runner) { r.Contention = 101 },
+		"no drain timeout": func(r *Runner) { r.DrainTimeout = 0 },
+		"no endpoints":     func(r *Runner) { r.Endpoints = nil },
+		"observe only":     func(r *Runner) { r.Endpoints[0].Role = "observe" },
+		"unknown strategy": func(r *Runner) { r.Strategy = "nearest" },
+	} {
+		r := valid()
+		invalidate(r)
+		if _, err := r.Run(context.Background(), w, ConstantRate(10)); err == nil {
+			t.Errorf("%v accepted", name)
+		}
+	}
+}
+
+func TestWorkloadOptionalHooks(t *testing.T) {
+	w := &workload{name: "noop"}
+	if err := w.Setup(context.Background(), nil); err != nil {
+		t.Errorf("setup = %v", err)
+	}
+	if _, ok := Workload(testWorkload{}).(Verifier); ok {
+		t.Error("a workload without Verify is a Verifier")
+	}
+	if v := w.Verify(context.Background(), nil); v != nil {
+		t.Errorf("verify = %v", v)
+	}
+	if err := w.Teardown(context.Background(), nil); err != nil {
+		t.Errorf("teardown = %v", err)
+	}
+}
+
+func TestVerifyWithoutSnapshot(t *testing.T) {
+	// snapshot fails, e.g. before init, so there is nothing to compare with
+	bc := &BenchmarkContext{Receipts: map[int]*types.Receipt{1: {Status: types.ReceiptStatusSuccessful}}}
+	w := ERC20MintWorkload(common.Address{}).(Verifier)
+	if v := w.Verify(context.Background(), bc); v != nil {
+		t.Errorf("verify = %v", v)
+	}
+}
+
+func testKeys(t *testing.T, n int) []*ecdsa.PrivateKey {
+	keys := make([]*ecdsa.PrivateKey, n)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+func TestConcurrentRunners(t *testing.T) {
+	// the gas limit and the statistics windows stay the ones of config
+	defer func(gasLimit uint64, window int) { config.GasLimit, config.RollingWindow = gasLimit, window }(config.GasLimit, config.RollingWindow)
+	config.GasLimit, config.RollingWindow = params.TxGas, 10
+	keys := testKeys(t, 8)
+	node := newTestNode(t, keys...)
+
+	// disjoint transfers verified afterwards, and transfers to the hot address only
+	disjoint := node.runner(keys[0], keys[:4], 3)
+	disjoint.Verify = true
+	hot := node.runner(keys[4], keys[4:], 4)
+	hot.Contention = 100
+
+	runners := []*Runner{disjoint, hot}
+	results := make([]*Result, len(runners))
+	errs := make([]error, len(runners))
+	var wg sync.WaitGroup
+	for i, r := range runners {
+		wg.Add(1)
+		go func(i int, r *Runner) {
+			defer wg.Done()
+			results[i], errs[i] = r.Run(context.Background(), NativeTransferWorkload(), ConstantRate(100))
+		}(i, r)
+	}
+	wg.Wait()
+
+	for i, r := range runners {
+		if errs[i] != nil {
+			t.Fatalf("run %d: %v", i, errs[i])
+		}
+		if result := results[i]; result.Confirmed != r.Total || result.Failed != 0 || result.Contention != r.Contention {
+			t.Errorf("run %d: %d of %d confirmed, %d failed, contention %d", i, result.Confirmed, r.Total, result.Failed, result.Contention)
+		}
+	}
+	if v := results[0].Verification; v == nil || v.Checked == 0 || len(v.Mismatches) > 0 {
+		t.Errorf("verification = %+v", v)
+	}
+	if v := results[1].Verification; v != nil {
+		t.Errorf("verified without Verify: %+v", v)
+	}
+	want := new(big.Int).Mul(big.NewInt(int64(hot.Total)), config.OneEther)
+	if got := node.balance(hotAddress); got == nil || got.Cmp(want) != 0 {
+		t.Errorf("hot address holds %v, want %v", got, want)
+	}
+}
+
+func TestMultiTransferFundsSenders(t *testing.T) {
+	defer func(gasLimit uint64, window, multi int) {
+		config.GasLimit, config.RollingWindow, config.Multi = gasLimit, window, multi
+	}(config.GasLimit, config.RollingWindow, config.Multi)
+	config.GasLimit, config.RollingWindow, config.Multi = params.TxGas, 10, 2
+	keys := testKeys(t, 3)
+	// only the owner holds coins at genesis
+	node := newTestNode(t, keys[0])
+
+	result, err := node.runner(keys[0], keys[1:], 4).Run(context.Background(), MultiTransferWorkload(), ConstantRate(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Confirmed != 4 || result.Failed != 0 {
+		t.Errorf("%d of 4 confirmed, %d failed", result.Confirmed, result.Failed)
+	}
+	for _, key := range keys[1:] {
+		if balance := node.balance(crypto.PubkeyToAddress(key.PublicKey)); balance == nil || balance.Sign() == 0 {
+			t.Errorf("sender holds %v", balance)
+		}
+	}
+}
+
+func TestERC20TransferFromRevokesAllowance(t *testing.T) {
+	defer func(window int) { config.RollingWindow = window }(config.RollingWindow)
+	config.RollingWindow = 10
+	keys := testKeys(t, 3)
+	node := newTestNode(t, keys...)
+	client, err := ethclient.Dial(node.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// the owner holds the tokens of one of the two transferFrom calls, the
+	// second account keys[2] spends them and neither recipient is the owner
+	auth, _ := bind.NewKeyedTransactorWithChainID(keys[0], config.ChainID)
+	auth.GasPrice = big.NewInt(2 * params.GWei)
+	address, tx, token, err := abi.DeployERC20(auth, client, config.OneEther)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = waitMined(context.Background(), client, bundledErrors, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	r := node.runner(keys[0], keys[1:], 2)
+	r.Verify = true
+	result, err := r.Run(context.Background(), ERC20TransferFromWorkload(address), ConstantRate(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Confirmed != 1 {
+		t.Errorf("%d of 2 confirmed", result.Confirmed)
+	}
+	if v := result.Verification; v == nil || v.Checked == 0 || len(v.Mismatches) > 0 {
+		t.Errorf("verification = %+v", v)
+	}
+	owner, spender := crypto.PubkeyToAddress(keys[0].PublicKey), crypto.PubkeyToAddress(keys[2].PublicKey)
+	if allowance, err := token.Allowance(&bind.CallOpts{}, owner, spender); err != nil || allowance.Sign() != 0 {
+		t.Errorf("allowance after teardown = %v, %v", allowance, err)
+	}
+}
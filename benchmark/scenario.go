@@ -0,0 +1,195 @@
+package benchmark
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"decipher.com/tps/config"
+	"gopkg.in/yaml.v2"
+)
+
+// Scenario is a named sequence of phases executed by `antps run`.
+type Scenario struct {
+	Name   string  `yaml:"name"`
+	Phases []Phase `yaml:"phases"`
+}
+
+// Phase runs one workload with a load profile. The amount of transactions is
+// either Count or derived from Duration (seconds). Pause is the number of
+// seconds to wait after the phase. Verify checks the on-chain state afterwards.
+// Contention is the percentage of transactions routed to the shared hot state.
+// Propagation measures the pool propagation of the transactions.
+// The options of the workload go under the key of its name.
+type Phase struct {
+	Name        string                 `yaml:"name"`
+	Workload    string                 `yaml:"workload"`
+	Profile     LoadProfile            `yaml:"profile"`
+	Count       int                    `yaml:"count"`
+	Duration    int                    `yaml:"duration"`
+	Pause       int                    `yaml:"pause"`
+	Verify      bool                   `yaml:"verify"`
+	Contention  int                    `yaml:"contention"`
+	Propagation bool                   `yaml:"propagation"`
+	Options     map[string]interface{} `yaml:",inline"`
+	options     Options
+}
+
+type PhaseResult struct {
+	Name     string  `json:"name"`
+	Workload string  `json:"workload"`
+	Result   *Result `json:"result,omitempty"`
+}
+
+type ScenarioReport struct {
+	Scenario string        `json:"scenario"`
+	Network  string        `json:"network"`
+	Started  time.Time     `json:"started"`
+	Phases   []PhaseResult `json:"phases"`
+}
+
+func LoadScenario(filename string) (*Scenario, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var scenario Scenario
+	if err = yaml.UnmarshalStrict(content, &scenario); err != nil {
+		return nil, err
+	}
+	if len(scenario.Phases) == 0 {
+		return nil, fmt.Errorf("scenario %q has no phases", scenario.Name)
+	}
+	for i := range scenario.Phases {
+		phase := &scenario.Phases[i]
+		if phase.Name == "" {
+			phase.Name = fmt.Sprintf("phase-%d", i+1)
+		}
+		if phase.Workload == "init" {
+			if len(phase.Options) > 0 {
+				return nil, fmt.Errorf("phase %q: init has no options", phase.Name)
+			}
+			continue
+		}
+		definition, ok := Lookup(phase.Workload)
+		if !ok {
+			return nil, fmt.Errorf("phase %q: unknown workload %q", phase.Name, phase.Workload)
+		}
+		if phase.options, err = decodeOptions(definition, phase.Options); err != nil {
+			return nil, fmt.Errorf("phase %q: %v", phase.Name, err)
+		}
+		if phase.Profile.Type == "" && phase.Profile.Rate == 0 {
+			phase.Profile = ConstantRate(config.Rate)
+		}
+		if err = phase.Profile.Validate(); err != nil {
+			return nil, fmt.Errorf("phase %q: %v", phase.Name, err)
+		}
+		if phase.Count == 0 && phase.Duration > 0 {
+			phase.Count = phase.Profile.CountFor(phase.Duration)
+		}
+		if phase.Count <= 0 {
+			return nil, fmt.Errorf("phase %q: count or duration is required", phase.Name)
+		}
+		if phase.Contention < 0 || phase.Contention > 100 {
+			return nil, fmt.Errorf("phase %q: contention must be between 0 and 100", phase.Name)
+		}
+		if phase.Contention > 0 && !definition.SupportsContention {
+			return nil, fmt.Errorf("phase %q: %v does not support contention", phase.Name, phase.Workload)
+		}
+		if phase.Verify && !definition.SupportsVerify {
+			return nil, fmt.Errorf("phase %q: %v does not support verify", phase.Name, phase.Workload)
+		}
+	}
+	return &scenario, nil
+}
+
+// decodeOptions returns the validated options of definition given under the
+// key of its name, nil if the workload has none.
+func decodeOptions(definition Definition, keys map[string]interface{}) (Options, error) {
+	for key := range keys {
+		if key != definition.Name || definition.Options == nil {
+			return nil, fmt.Errorf("unknown key %q", key)
+		}
+	}
+	if definition.Options == nil {
+		return nil, nil
+	}
+	options := definition.Options()
+	content, err := yaml.Marshal(keys[definition.Name])
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.UnmarshalStrict(content, options); err != nil {
+		return nil, fmt.Errorf("%v: %v", definition.Name, err)
+	}
+	return options, options.Validate()
+}
+
+// accounts returns the number of accounts the phase sends from.
+func (phase Phase) accounts() int {
+	definition, ok := Lookup(phase.Workload)
+	if !ok {
+		return 0
+	}
+	accounts := definition.AccountsFor(phase.Count)
+	if options, ok := phase.options.(interface{ AccountsFor(total int) int }); ok {
+		accounts = max(accounts, options.AccountsFor(phase.Count))
+	}
+	return accounts
+}
+
+// runner returns the Runner of the phase: the configured one with the count,
+// contention, propagation and verification of the phase.
+func (phase Phase) runner() *Runner {
+	r := NewRunner(phase.Count)
+	r.Contention = phase.Contention
+	r.Propagation = phase.Propagation
+	r.Verify = phase.Verify
+	return r
+}
+
+// run runs the workload of the phase with its options and exits on setup errors.
+func (phase Phase) run() *Result {
+	definition, _ := Lookup(phase.Workload)
+	if phase.options == nil {
+		return definition.Run(phase.runner(), phase.Profile)
+	}
+	w, err := phase.options.Workload()
+	if err != nil {
+		log.Fatalf("Invalid %v workload: %v", phase.Workload, err)
+	}
+	return run(phase.runner(), phase.Profile, w)
+}
+
+func RunScenario(scenario *Scenario) *ScenarioReport {
+	accounts := 2
+	for _, phase := range scenario.Phases {
+		accounts = max(accounts, phase.accounts())
+	}
+	InitAccount(accounts)
+
+	report := &ScenarioReport{
+		Scenario: scenario.Name,
+		Network:  config.Network,
+		Started:  time.Now(),
+	}
+	for i, phase := range scenario.Phases {
+		log.Printf("===== phase %d/%d: %s (%s) =====\n", i+1, len(scenario.Phases), phase.Name, phase.Workload)
+		phaseResult := PhaseResult{Name: phase.Name, Workload: phase.Workload}
+		if phase.Workload == "init" {
+			UpdateAddress(InitContract())
+		} else {
+			phaseResult.Result = phase.run()
+		}
+		report.Phases = append(report.Phases, phaseResult)
+
+		if phase.Pause > 0 && i < len(scenario.Phases)-1 {
+			time.Sleep(time.Duration(phase.Pause) * time.Second)
+		}
+	}
+
+	filename := fmt.Sprintf("%v.%v.%v.scenario.json", config.Network, report.Started.Format("20060102_150405"), scenario.Name)
+	StoreReport(report, filename)
+	return report
+}
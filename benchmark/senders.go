@@ -0,0 +1,96 @@
+package benchmark
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tx is the transaction Index of 1..total a Workload sends. Opts signs as
+// Sender with Nonce and carries the gas price and limit of the run.
+type Tx struct {
+	Index  int
+	Sender common.Address
+	Nonce  uint64
+	Opts   *bind.TransactOpts
+}
+
+// sender is an account sending the transactions of a run. Its nonces are
+// counted from the pending nonce of the node, which is fetched again after a
+// failed transaction, so the concurrent transactions of one sender do not
+// collide. The gas price is suggested again with the nonce, so a retry does
+// not resend a price the base fee has outgrown.
+type sender struct {
+	address  common.Address
+	key      *ecdsa.PrivateKey
+	opts     *bind.TransactOpts
+	pending  func() (uint64, error)
+	suggest  func() (*big.Int, error)
+	mutex    sync.Mutex
+	next     uint64
+	gasPrice *big.Int
+	synced   bool
+}
+
+// newSender reads the nonces and gas prices of privateKey's account over the
+// pooled connections of backend.
+func newSender(backend *submitRouter, privateKey *ecdsa.PrivateKey) *sender {
+	_, opts, address := initialize(backend.reader(), privateKey)
+	return &sender{
+		address: address,
+		key:     privateKey,
+		opts:    opts,
+		pending: func() (uint64, error) {
+			return backend.PendingNonceAt(context.Background(), address)
+		},
+		suggest: func() (*big.Int, error) {
+			return backend.SuggestGasPrice(context.Background())
+		},
+	}
+}
+
+// nonce returns the next nonce of s and the gas price to send it with.
+func (s *sender) nonce() (uint64, *big.Int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.synced {
+		nonce, err := s.pending()
+		if err != nil {
+			return 0, nil, err
+		}
+		gasPrice := s.opts.GasPrice
+		if s.suggest != nil {
+			if gasPrice, err = s.suggest(); err != nil {
+				return 0, nil, err
+			}
+		}
+		s.next, s.gasPrice, s.synced = nonce, gasPrice, true
+	}
+	nonce := s.next
+	s.next++
+	return nonce, s.gasPrice, nil
+}
+
+// resync fetches the pending nonce and the gas price again for the next transaction.
+func (s *sender) resync() {
+	s.mutex.Lock()
+	s.synced = false
+	s.mutex.Unlock()
+}
+
+// tx returns transaction index with the next nonce of s.
+func (s *sender) tx(ctx context.Context, index int) (Tx, error) {
+	nonce, gasPrice, err := s.nonce()
+	if err != nil {
+		return Tx{}, err
+	}
+	opts := *s.opts
+	opts.Nonce = new(big.Int).SetUint64(nonce)
+	opts.GasPrice = gasPrice
+	opts.Context = ctx
+	return Tx{Index: index, Sender: s.address, Nonce: nonce, Opts: &opts}, nil
+}
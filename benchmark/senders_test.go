@@ -0,0 +1,62 @@
+package benchmark
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"decipher.com/tps/config"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSenderNonces(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, config.ChainID)
+	fetched, pending, price := 0, uint64(5), big.NewInt(10)
+	s := &sender{address: opts.From, opts: opts, pending: func() (uint64, error) {
+		fetched++
+		return pending, nil
+	}, suggest: func() (*big.Int, error) {
+		return price, nil
+	}}
+
+	for want := uint64(5); want < 8; want++ {
+		tx, err := s.tx(context.Background(), int(want))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tx.Nonce != want || tx.Opts.Nonce.Uint64() != want || tx.Sender != opts.From || tx.Index != int(want) {
+			t.Errorf("tx = %+v, want nonce %v", tx, want)
+		}
+	}
+	if fetched != 1 || opts.Nonce != nil {
+		t.Errorf("fetched %v times, shared options nonce %v", fetched, opts.Nonce)
+	}
+
+	// a failed transaction left nonce 6 unused and the base fee went up
+	pending, price = 6, big.NewInt(20)
+	s.resync()
+	if tx, _ := s.tx(context.Background(), 4); tx.Nonce != 6 || tx.Opts.GasPrice.Cmp(price) != 0 || fetched != 2 {
+		t.Errorf("nonce after resync = %v at gas price %v, fetched %v times", tx.Nonce, tx.Opts.GasPrice, fetched)
+	}
+}
+
+func TestSenderRotation(t *testing.T) {
+	keys := []*ecdsa.PrivateKey{}
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, key)
+	}
+	bc := &BenchmarkContext{}
+	for _, key := range keys {
+		opts, _ := bind.NewKeyedTransactorWithChainID(key, config.ChainID)
+		bc.senders = append(bc.senders, &sender{address: opts.From, opts: opts})
+	}
+	for id, want := range []int{0, 1, 2, 0} {
+		if got := bc.sender(id + 1); got != bc.senders[want] {
+			t.Errorf("sender of %v = %v, want %v", id+1, got.address, bc.senders[want].address)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package benchmark
+
+import (
+	"math"
+	"slices"
+)
+
+// Summary describes a metric over repeated trials. CILow and CIHigh bound the
+// 95% confidence interval of the mean.
+type Summary struct {
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"`
+	CILow  float64 `json:"ci95_low"`
+	CIHigh float64 `json:"ci95_high"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// two-sided 95% critical values of Student's t distribution, indexed by degrees of freedom
+var tTable = []float64{0, 12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042}
+
+func tCritical(df int) float64 {
+	if df < len(tTable) {
+		return tTable[df]
+	}
+	return 1.96
+}
+
+func summarize(values []float64) Summary {
+	if len(values) == 0 {
+		return Summary{}
+	}
+	s := Summary{Min: values[0], Max: values[0]}
+	for _, v := range values {
+		s.Mean += v
+		s.Min = math.Min(s.Min, v)
+		s.Max = math.Max(s.Max, v)
+	}
+	s.Mean /= float64(len(values))
+	s.CILow, s.CIHigh = s.Mean, s.Mean
+	if len(values) < 2 {
+		return s
+	}
+
+	for _, v := range values {
+		s.StdDev += (v - s.Mean) * (v - s.Mean)
+	}
+	s.StdDev = math.Sqrt(s.StdDev / float64(len(values)-1))
+	margin := tCritical(len(values)-1) * s.StdDev / math.Sqrt(float64(len(values)))
+	s.CILow, s.CIHigh = s.Mean-margin, s.Mean+margin
+	return s
+}
+
+// percentile returns the nearest-rank p-th percentile (0 < p <= 100) of values.
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,38 @@
+package benchmark
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	s := summarize([]float64{10, 12, 14})
+	if s.Mean != 12 || s.Min != 10 || s.Max != 14 {
+		t.Fatalf("unexpected summary %+v", s)
+	}
+	if s.StdDev != 2 {
+		t.Fatalf("stddev = %v, want 2", s.StdDev)
+	}
+	margin := 4.303 * 2 / math.Sqrt(3)
+	if math.Abs(s.CILow-(12-margin)) > 1e-9 || math.Abs(s.CIHigh-(12+margin)) > 1e-9 {
+		t.Fatalf("unexpected confidence interval %v..%v", s.CILow, s.CIHigh)
+	}
+
+	single := summarize([]float64{5})
+	if single.StdDev != 0 || single.CILow != 5 || single.CIHigh != 5 {
+		t.Fatalf("unexpected single trial summary %+v", single)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{5, 1, 4, 2, 3, 6, 7, 8, 9, 10}
+	cases := map[float64]float64{50: 5, 90: 9, 95: 10, 99: 10, 1: 1}
+	for p, want := range cases {
+		if got := percentile(values, p); got != want {
+			t.Errorf("percentile(%v) = %v, want %v", p, got, want)
+		}
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("percentile of empty = %v, want 0", got)
+	}
+}
@@ -0,0 +1,117 @@
+package benchmark
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log"
+	"math/big"
+
+	"decipher.com/tps/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// SyntheticOptions selects the call of the synthetic contract. Intensity is
+// the number of keccak rounds, fresh storage slots, calldata bytes or logs.
+type SyntheticOptions struct {
+	Kind      string `yaml:"kind"`
+	Intensity int    `yaml:"intensity"`
+}
+
+func (opts SyntheticOptions) Validate() error {
+	switch opts.Kind {
+	case "keccak", "sstore", "calldata", "logs":
+	default:
+		return fmt.Errorf("unknown synthetic kind %q", opts.Kind)
+	}
+	if opts.Intensity <= 0 {
+		return fmt.Errorf("intensity must be positive")
+	}
+	return nil
+}
+
+// Workload returns the synthetic workload of opts.
+func (opts SyntheticOptions) Workload() (Workload, error) {
+	return SyntheticWorkload(opts)
+}
+
+// Synthetic deploys the synthetic contract and benchmarks one of its calls.
+func Synthetic(total int, profile LoadProfile, opts SyntheticOptions) *Result {
+	w, err := SyntheticWorkload(opts)
+	if err != nil {
+		log.Fatalf("Invalid synthetic options: %v", err)
+	}
+	return run(NewRunner(total), profile, w)
+}
+
+// SyntheticWorkload deploys the synthetic contract and calls opts.Kind. The
+// gas limit is estimated from the first call, as it grows with the intensity.
+func SyntheticWorkload(opts SyntheticOptions) (Workload, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	var contract *abi.Synthetic
+	var address common.Address
+	intensity := big.NewInt(int64(opts.Intensity))
+	payload := make([]byte, opts.Intensity)
+	rand.Read(payload)
+
+	call := func(chain *bind.TransactOpts) (*types.Transaction, error) {
+		switch opts.Kind {
+		case "keccak":
+			return contract.Keccak(chain, intensity)
+		case "sstore":
+			return contract.Store(chain, intensity)
+		case "calldata":
+			return contract.Payload(chain, payload)
+		default:
+			return contract.EmitLogs(chain, intensity)
+		}
+	}
+	return &workload{
+		name: opts.Kind + "_synthetic",
+		setup: func(ctx context.Context, bc *BenchmarkContext) error {
+			var err error
+			address, err = bc.Deploy(ctx, "Synthetic contract", func(chain *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, error) {
+				address, tx, _, err := abi.DeploySynthetic(chain, backend)
+				return address, tx, err
+			})
+			if err != nil {
+				return err
+			}
+			bc.ContractAddress = address
+			contract, _ = abi.NewSynthetic(address, bc.Backend)
+
+			estimate := *bc.Chain
+			estimate.GasLimit = 0
+			estimate.NoSend = true
+			tx, err := call(&estimate)
+			if err != nil {
+				return fmt.Errorf("estimate gas: %w", err)
+			}
+			bc.Chain.GasLimit = tx.Gas() * 6 / 5
+			log.Printf("%v intensity %v, gas limit %v\n", opts.Kind, opts.Intensity, bc.Chain.GasLimit)
+			return nil
+		},
+		transaction: func(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error) {
+			return call(tx.Opts)
+		},
+		verify: func(ctx context.Context, bc *BenchmarkContext) *Verification {
+			if opts.Kind != "sstore" {
+				return nil
+			}
+			// slot 0 counts the written slots
+			expected := big.NewInt(int64(len(bc.Succeeded()) * opts.Intensity))
+			v := &Verification{Checked: 1}
+			value, err := bc.Client.StorageAt(ctx, address, common.Hash{}, nil)
+			if err != nil {
+				v.Mismatches = append(v.Mismatches, Mismatch{"synthetic_slots", address.Hex(), expected.String(), err.Error()})
+			} else if actual := new(big.Int).SetBytes(value); actual.Cmp(expected) != 0 {
+				v.Mismatches = append(v.Mismatches, Mismatch{"synthetic_slots", address.Hex(), expected.String(), actual.String()})
+			}
+			return v.log()
+		},
+	}, nil
+}
@@ -0,0 +1,40 @@
+package benchmark
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"decipher.com/tps/abi"
+)
+
+// The synthetic contract is hand assembled, so its dispatcher must be checked
+// against the ABI of the binding.
+func TestSyntheticSelectors(t *testing.T) {
+	parsed, err := abi.SyntheticMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, method := range parsed.Methods {
+		// PUSH4 <selector> in the runtime code
+		if !strings.Contains(abi.SyntheticMetaData.Bin, "63"+hex.EncodeToString(method.ID)) {
+			t.Errorf("selector of %v is not dispatched", name)
+		}
+	}
+	for name, event := range parsed.Events {
+		if !strings.Contains(abi.SyntheticMetaData.Bin, "7f"+hex.EncodeToString(event.ID[:])) {
+			t.Errorf("topic of %v is not emitted", name)
+		}
+	}
+}
+
+func TestSyntheticOptions(t *testing.T) {
+	if err := (SyntheticOptions{Kind: "sstore", Intensity: 100}).Validate(); err != nil {
+		t.Error(err)
+	}
+	for _, bad := range []SyntheticOptions{{Kind: "sload", Intensity: 1}, {Kind: "keccak"}} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
@@ -11,8 +11,11 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 type blockTPSInfo struct {
@@ -20,106 +23,266 @@ type blockTPSInfo struct {
 	pendingTransaction   int
 	confirmedTransaction int
 	tps                  uint64
+	elapsed              float64
+	operations           int
+	gasUsed              uint64
+	gasLimit             uint64
+	baseFee              uint64
+	size                 uint64
+	txBytes              uint64
+	offered              float64
 }
 
-func CheckTpsByBlock(total int, filename string) {
-	config.WaitSubscribeBlockHead.Add(1)
-	defer config.WaitSubscribeBlockHead.Done()
+// blockWatcher counts the blocks of one run on its own goroutine. The run ends
+// its send phase with drain and waits for the block file with wait.
+type blockWatcher struct {
+	start    chan time.Time
+	drainReq chan drainRequest
+	finished chan int
+	done     chan struct{}
+}
 
-	client, err := ethclient.Dial(config.Host2)
-	if err != nil {
-		log.Fatal("Fail to Dial", err)
+// drainRequest ends the send phase: failed transactions will not be mined and
+// the watcher finalizes at deadline at the latest.
+type drainRequest struct {
+	failed   int
+	deadline time.Time
+}
+
+// startBlockWatcher starts counting the blocks of a run of total transactions
+// once the watcher is connected, and returns when it is.
+func startBlockWatcher(url string, countMode string, total int, filename string, result *Result, target WatchTarget) *blockWatcher {
+	w := &blockWatcher{
+		start:    make(chan time.Time),
+		drainReq: make(chan drainRequest),
+		finished: make(chan int),
+		done:     make(chan struct{}),
 	}
-	defer client.Close()
-	ctx := context.Background()
-	client2 := client.Client()
+	go w.run(url, countMode, total, filename, result, target)
+	w.start <- time.Now()
+	return w
+}
+
+// drain reports the failed transactions and returns the confirmed count once
+// the others are counted, or at deadline.
+func (w *blockWatcher) drain(failed int, deadline time.Time) int {
+	w.drainReq <- drainRequest{failed: failed, deadline: deadline}
+	return <-w.finished
+}
+
+// wait returns when the block file is written and the watcher stopped.
+func (w *blockWatcher) wait() {
+	<-w.done
+}
 
+// run watches the new blocks of the node at url until all transactions are
+// confirmed and records the block based throughput into result. With countMode
+// "events" only the successful operations of target are counted as confirmed. The canonical
+// chain is followed by parent hashes: the transactions of blocks orphaned by a
+// reorg are no longer counted and count again when re-included.
+func (w *blockWatcher) run(url string, countMode string, total int, filename string, result *Result, target WatchTarget) {
+	defer close(w.done)
+
+	ctx := context.Background()
 	headers := make(chan *types.Header)
-	sub, err := client.SubscribeNewHead(ctx, headers)
-	if err != nil {
-		log.Fatal("Fail to subscribe", err)
+	var (
+		client          *ethclient.Client
+		client2         *rpc.Client
+		sub             ethereum.Subscription
+		countOperations operationCounter
+	)
+	connect := func() {
+		client, sub = watchHeads(ctx, url, headers)
+		client2 = client.Client()
+		if countMode == "events" {
+			countOperations = newOperationCounter(client, target)
+		}
 	}
+	connect()
+	defer func() {
+		sub.Unsubscribe()
+		client.Close()
+	}()
 	totalTransactions := 0
+	totalOperations := 0
+	confirmed := func() int {
+		if countOperations != nil {
+			return totalOperations
+		}
+		return totalTransactions
+	}
 
 	recordAvgTPS := make(map[int]blockTPSInfo)
+	chain := newChainTracker()
+	uncles := 0
+
+	lastSent := int64(0)
+	maxTPS, totalDelay := 0.0, 0.0
+	written := make(chan struct{})
 
-	startTime := <-config.ChStart
+	startTime := <-w.start
 	startConsensusTime := startTime
 	blockNumber := 0
 	failCount := -1
-	sendFinish := false
-	writeFile := false
+	var drainTimeout <-chan time.Time
+	finished := false
+	// finish summarizes the run once every transaction is confirmed or failed,
+	// or when the drain phase times out, and stops counting blocks.
+	finish := func() {
+		if finished {
+			return
+		}
+		finished = true
+		log.Printf("max tps = %v\n", maxTPS)
+		log.Printf("total delay = %v\n\n", totalDelay)
+		result.Duration, result.MaxTPS = totalDelay, maxTPS
+		summarizeBlocks(recordAvgTPS, result)
+		chain.summarize(result)
+		result.Uncles = uncles
+		go func() {
+			StoreDataOnFile(recordAvgTPS, filename)
+			close(written)
+		}()
+		w.finished <- confirmed()
+	}
 	for {
 		select {
-		case err = <-sub.Err():
-			log.Fatal("Failed to subscribe", err)
-		case failCount = <-config.ChFailedCount:
+		case err := <-sub.Err():
+			// the heads missed until the next one are fetched by their parent hash
+			log.Println("head subscription:", err, "- reconnecting")
+			sub.Unsubscribe()
+			client.Close()
+			connect()
+		case drain := <-w.drainReq:
+			failCount = drain.failed
 			log.Println("failed to count:", failCount)
-			if totalTransactions >= total-failCount {
-				if !sendFinish {
-					sendFinish = true
-					config.ChFinish <- totalTransactions
-				}
-				if !writeFile {
-					writeFile = true
-					go StoreDataOnFile(recordAvgTPS, filename)
-					log.Printf("max tps = %v\n", config.MaxTPS)
-					log.Printf("max delay = %v\n", config.MaxBlockTime)
-					log.Printf("total delay = %v\n\n", config.TotalDelay)
-				}
+			if confirmed() >= total-failCount {
+				finish()
+			} else {
+				log.Printf("===== drain: waiting for %v transactions until %v =====\n", total-failCount-confirmed(), drain.deadline.Format(time.TimeOnly))
+				drainTimeout = time.After(time.Until(drain.deadline))
 			}
+		case <-drainTimeout:
+			log.Printf("drain timed out with %v transactions unconfirmed\n", total-failCount-confirmed())
+			result.DrainTimeout = true
+			finish()
 
 		case header := <-headers:
+			if finished {
+				continue
+			}
 			currentDelay := time.Since(startConsensusTime).Seconds()
 			startConsensusTime = time.Now()
-			config.TotalDelay = time.Since(startTime).Seconds()
-			block, err := client.BlockByNumber(ctx, header.Number)
+			totalDelay = time.Since(startTime).Seconds()
+			// a head that cannot be fetched is counted with the next one
+			head, err := client.BlockByHash(ctx, header.Hash())
 			if err != nil {
-				log.Fatal("getBlock ", err)
+				log.Println("getBlock", err)
+				continue
 			}
-
-			transactions := len(block.Transactions())
-			totalTransactions += transactions
-			currentTps := float64(transactions) / currentDelay
-			tps := float64(totalTransactions) / config.TotalDelay
-
-			client2.CallContext(ctx, &config.Result, "txpool_status")
-			pendingTransaction, _ := strconv.ParseInt(config.Result["pending"], 0, 64)
-			queuedTransaction, _ := strconv.ParseInt(config.Result["queued"], 0, 64)
-
-			log.Printf("===== block no. %v =====\n", block.Number().Uint64())
-			log.Printf("confrimed_transactions:%v\n", transactions)
-			log.Printf("total_confirmed_transactions:%v\n", totalTransactions)
-			log.Printf("pending_transactions:%v\n", pendingTransaction)
-			log.Printf("queued_transactions:%v\n", queuedTransaction)
-			log.Printf("block_latency:  %v\n", currentDelay)
-			log.Printf("current_tps:%v\n", currentTps)
-			log.Printf("total_tps:%v\n\n", tps)
-
-			blockNumber = int(block.NumberU64())
-			recordAvgTPS[blockNumber] = blockTPSInfo{int(currentDelay), int(pendingTransaction), transactions, uint64(tps)}
-
-			if tps > config.MaxTPS {
-				config.MaxTPS = tps
+			var oldHead common.Hash
+			if chain.tip != nil {
+				oldHead = chain.tip.hash
 			}
-			if failCount >= 0 {
-				// TODO: Should be add Timeout Logic?
-				if totalTransactions >= total-failCount {
-					log.Printf("max tps = %v\n", config.MaxTPS)
-					log.Printf("max delay = %v\n\n", config.MaxBlockTime)
-					log.Printf("total delay = %v\n\n", config.TotalDelay)
-					if !writeFile {
-						writeFile = true
-						go StoreDataOnFile(recordAvgTPS, filename)
-					}
-					if !sendFinish {
-						sendFinish = true
-						config.ChFinish <- totalTransactions
+			blocks, orphaned, err := chain.advance(head, func(hash common.Hash) (*types.Block, error) {
+				return client.BlockByHash(ctx, hash)
+			})
+			if err != nil {
+				log.Println("getBlock", err)
+				continue
+			}
+			if len(orphaned) > 0 {
+				chain.reorg(totalDelay, oldHead, head, orphaned)
+				for _, block := range orphaned {
+					totalTransactions -= len(block.txs)
+					totalOperations -= block.operations
+					delete(recordAvgTPS, int(block.number))
+				}
+			}
+
+			tps := 0.0
+			for i, block := range blocks {
+				// the fetched ancestors of the head are counted without a delay
+				delay := 0.0
+				if i == len(blocks)-1 {
+					delay = currentDelay
+				}
+				transactions := len(block.Transactions())
+				totalTransactions += transactions
+				tps = float64(totalTransactions) / totalDelay
+				operations := 0
+				if countOperations != nil {
+					operations, err = countOperations(ctx, block)
+					if err != nil {
+						log.Println("failed to count operations:", err)
 					}
+					totalOperations += operations
+				}
+				chain.record(block, operations)
+
+				var status map[string]string
+				client2.CallContext(ctx, &status, "txpool_status")
+				pendingTransaction, _ := strconv.ParseInt(status["pending"], 0, 64)
+				queuedTransaction, _ := strconv.ParseInt(status["queued"], 0, 64)
+
+				log.Printf("===== block no. %v =====\n", block.Number().Uint64())
+				log.Printf("confrimed_transactions:%v\n", transactions)
+				log.Printf("total_confirmed_transactions:%v\n", totalTransactions)
+				if countOperations != nil {
+					log.Printf("successful_operations:%v\n", operations)
+					log.Printf("total_successful_operations:%v\n", totalOperations)
+				}
+				log.Printf("pending_transactions:%v\n", pendingTransaction)
+				log.Printf("queued_transactions:%v\n", queuedTransaction)
+				log.Printf("block_latency:  %v\n", delay)
+				if delay > 0 {
+					log.Printf("current_tps:%v\n", float64(transactions)/delay)
+				}
+				log.Printf("total_tps:%v\n\n", tps)
+
+				baseFee := uint64(0)
+				if block.BaseFee() != nil {
+					baseFee = block.BaseFee().Uint64()
+				}
+				txBytes := uint64(0)
+				for _, tx := range block.Transactions() {
+					txBytes += tx.Size()
+				}
+				log.Printf("gas_used:%v/%v\n", block.GasUsed(), block.GasLimit())
+				uncles += len(block.Uncles())
+				// offered load: transactions submitted since the previous block
+				offered := 0.0
+				if target.Sent != nil && delay > 0 {
+					sent := target.Sent.Load()
+					offered = float64(sent-lastSent) / delay
+					lastSent = sent
 				}
+
+				blockNumber = int(block.NumberU64())
+				recordAvgTPS[blockNumber] = blockTPSInfo{
+					blockDelay:           int(delay),
+					pendingTransaction:   int(pendingTransaction),
+					confirmedTransaction: transactions,
+					tps:                  uint64(tps),
+					elapsed:              totalDelay,
+					operations:           operations,
+					gasUsed:              block.GasUsed(),
+					gasLimit:             block.GasLimit(),
+					baseFee:              baseFee,
+					size:                 block.Size(),
+					txBytes:              txBytes,
+					offered:              offered,
+				}
+			}
+
+			if tps > maxTPS {
+				maxTPS = tps
+			}
+			if failCount >= 0 && confirmed() >= total-failCount {
+				finish()
 			}
 
-		case <-config.ChFileWriteFinish:
+		case <-written:
 			log.Println("file write finished")
 			return
 		}
@@ -127,6 +290,114 @@ func CheckTpsByBlock(total int, filename string) {
 
 }
 
+// summarizeBlocks computes the whole-run, steady-state and peak rolling-window TPS
+// and the gas throughput and utilization of the blocks observed over
+// result.Duration seconds. Warm-up and cool-down are excluded by seconds or,
+// with windowUnit "blocks", by blocks.
+func summarizeBlocks(data map[int]blockTPSInfo, result *Result) {
+	keys := make([]int, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	blocks := make([]blockTPSInfo, 0, len(keys))
+	for _, k := range keys {
+		blocks = append(blocks, data[k])
+	}
+
+	gasLimit, size, txBytes := uint64(0), uint64(0), uint64(0)
+	for _, block := range blocks {
+		result.Transactions += block.confirmedTransaction
+		result.Operations += block.operations
+		result.BlockGasUsed += block.gasUsed
+		gasLimit += block.gasLimit
+		size += block.size
+		txBytes += block.txBytes
+	}
+	if result.Duration > 0 {
+		result.AvgTPS = float64(result.Transactions) / result.Duration
+		result.OperationTPS = float64(result.Operations) / result.Duration
+		result.BlockGasPerSecond = float64(result.BlockGasUsed) / result.Duration
+	}
+	if gasLimit > 0 {
+		result.BlockFullness = 100 * float64(result.BlockGasUsed) / float64(gasLimit)
+	}
+	if len(blocks) > 0 {
+		result.AvgBlockSize = float64(size) / float64(len(blocks))
+	}
+	if result.Transactions > 0 {
+		result.AvgTxSize = float64(txBytes) / float64(result.Transactions)
+	}
+	log.Printf("gas throughput = %.3f Mgas/s, block fullness = %.1f%%\n", result.BlockGasPerSecond/1e6, result.BlockFullness)
+	result.SteadyTPS, result.SteadyBlocks = steadyStateTPS(blocks, config.WarmUp, config.CoolDown, config.WindowUnit == "blocks")
+	result.PeakWindowTPS = peakWindowTPS(blocks, config.RollingWindow)
+	log.Printf("steady state tps = %v (%v blocks)\n", result.SteadyTPS, result.SteadyBlocks)
+	log.Printf("peak %vs window tps = %v\n", config.RollingWindow, result.PeakWindowTPS)
+}
+
+func steadyStateTPS(blocks []blockTPSInfo, warmUp int, coolDown int, byBlocks bool) (float64, int) {
+	if len(blocks) == 0 {
+		return 0, 0
+	}
+	first, last := 0, len(blocks)
+	if byBlocks {
+		first, last = warmUp, len(blocks)-coolDown
+	} else {
+		end := blocks[len(blocks)-1].elapsed
+		for first < last && blocks[first].elapsed < float64(warmUp) {
+			first++
+		}
+		for last > first && blocks[last-1].elapsed > end-float64(coolDown) {
+			last--
+		}
+	}
+	if first >= last {
+		return 0, 0
+	}
+
+	start := 0.0
+	if first > 0 {
+		start = blocks[first-1].elapsed
+	}
+	transactions := 0
+	for _, block := range blocks[first:last] {
+		transactions += block.confirmedTransaction
+	}
+	span := blocks[last-1].elapsed - start
+	if span <= 0 {
+		return 0, last - first
+	}
+	return float64(transactions) / span, last - first
+}
+
+// peakWindowTPS returns the highest TPS over any window of the given seconds.
+// If the run is shorter than the window, the whole run is used.
+func peakWindowTPS(blocks []blockTPSInfo, window int) float64 {
+	if len(blocks) == 0 {
+		return 0
+	}
+	size := float64(window)
+	lo, transactions, peak := 0, 0, 0.0
+	for _, block := range blocks {
+		transactions += block.confirmedTransaction
+		for blocks[lo].elapsed <= block.elapsed-size {
+			transactions -= blocks[lo].confirmedTransaction
+			lo++
+		}
+		if block.elapsed >= size {
+			peak = max(peak, float64(transactions)/size)
+		}
+	}
+	if peak == 0 && blocks[len(blocks)-1].elapsed > 0 {
+		all := 0
+		for _, block := range blocks {
+			all += block.confirmedTransaction
+		}
+		peak = float64(all) / blocks[len(blocks)-1].elapsed
+	}
+	return peak
+}
+
 func StoreDataOnFile(data map[int]blockTPSInfo, filename string) {
 	file, err := os.Create(filepath.Join(".", "result", filename))
 	if err != nil {
@@ -143,7 +414,7 @@ func StoreDataOnFile(data map[int]blockTPSInfo, filename string) {
 	slices.Sort(keys)
 
 	for _, k := range keys {
-		fmt.Fprintf(file, "%d	%d    %d	%d   %d\n", k, data[k].blockDelay, data[k].pendingTransaction, data[k].confirmedTransaction, data[k].tps)
+		fmt.Fprintf(file, "%d	%d    %d	%d   %d	%d	%d	%d	%d	%d\n", k, data[k].blockDelay, data[k].pendingTransaction, data[k].confirmedTransaction, data[k].tps,
+			data[k].gasUsed, data[k].gasLimit, data[k].baseFee, data[k].size, int(data[k].offered))
 	}
-	config.ChFileWriteFinish <- true
 }
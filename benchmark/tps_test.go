@@ -0,0 +1,129 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"decipher.com/tps/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func testBlocks() []blockTPSInfo {
+	// one block every two seconds: ramp, steady 100 tx blocks, tail
+	txs := []int{10, 50, 100, 100, 100, 100, 40, 5}
+	blocks := make([]blockTPSInfo, len(txs))
+	for i, n := range txs {
+		blocks[i] = blockTPSInfo{confirmedTransaction: n, elapsed: float64(2 * (i + 1))}
+	}
+	return blocks
+}
+
+func TestSteadyStateTPS(t *testing.T) {
+	blocks := testBlocks()
+
+	tps, n := steadyStateTPS(blocks, 2, 2, true)
+	if n != 4 || tps != 50 {
+		t.Fatalf("by blocks: tps = %v over %v blocks, want 50 over 4", tps, n)
+	}
+
+	// seconds: drop blocks observed before 5s and after 16-4=12s
+	tps, n = steadyStateTPS(blocks, 5, 4, false)
+	if n != 4 || tps != 50 {
+		t.Fatalf("by seconds: tps = %v over %v blocks, want 50 over 4", tps, n)
+	}
+
+	tps, n = steadyStateTPS(blocks, 0, 0, false)
+	if n != len(blocks) || tps != 505.0/16 {
+		t.Fatalf("whole run: tps = %v over %v blocks", tps, n)
+	}
+
+	if tps, n = steadyStateTPS(blocks, 5, 5, true); tps != 0 || n != 0 {
+		t.Fatalf("excluding everything: tps = %v over %v blocks", tps, n)
+	}
+}
+
+func TestPeakWindowTPS(t *testing.T) {
+	blocks := testBlocks()
+	if got := peakWindowTPS(blocks, 4); got != 50 {
+		t.Fatalf("4s window = %v, want 50", got)
+	}
+	if got := peakWindowTPS(blocks, 100); got != 505.0/16 {
+		t.Fatalf("window longer than run = %v, want whole run", got)
+	}
+}
+
+func TestSummarizeBlockGas(t *testing.T) {
+	defer func(window int) { config.RollingWindow = window }(config.RollingWindow)
+	config.RollingWindow = 10
+
+	data := map[int]blockTPSInfo{
+		1: {confirmedTransaction: 100, elapsed: 2, gasUsed: 2100000, gasLimit: 8000000, size: 11000, txBytes: 10000},
+		2: {confirmedTransaction: 100, elapsed: 4, gasUsed: 5900000, gasLimit: 8000000, size: 11000, txBytes: 10000},
+	}
+	result := &Result{Duration: 4}
+	summarizeBlocks(data, result)
+	if result.BlockGasUsed != 8000000 || result.BlockGasPerSecond != 2000000 {
+		t.Errorf("gas = %v, %v per second", result.BlockGasUsed, result.BlockGasPerSecond)
+	}
+	if result.BlockFullness != 50 {
+		t.Errorf("fullness = %v, want 50", result.BlockFullness)
+	}
+	if result.AvgBlockSize != 11000 || result.AvgTxSize != 100 {
+		t.Errorf("block size = %v, tx size = %v", result.AvgBlockSize, result.AvgTxSize)
+	}
+}
+
+func TestCompleteResultUnconfirmed(t *testing.T) {
+	failures := newFailureRecorder()
+	failures.record(errors.New("insufficient funds for gas * price + value"))
+	result := completeResult(&Result{DrainTimeout: true}, "transfer_native", "", 10, 6, failures, 0, nil)
+	if result.Failed != 1 || result.Unconfirmed != 3 {
+		t.Errorf("failed = %v, unconfirmed = %v", result.Failed, result.Unconfirmed)
+	}
+	result = completeResult(&Result{}, "transfer_native", "", 10, 12, newFailureRecorder(), 0, nil)
+	if result.Unconfirmed != 0 {
+		t.Errorf("unconfirmed = %v with foreign transactions", result.Unconfirmed)
+	}
+}
+
+func TestWatcherDrainTimeout(t *testing.T) {
+	defer func(window int) { config.RollingWindow = window }(config.RollingWindow)
+	config.RollingWindow = 10
+	key, _ := crypto.GenerateKey()
+	node := newTestNode(t, key)
+	client, err := ethclient.Dial(node.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// one of three transactions is mined, the others are never sent
+	result := &Result{}
+	w := startBlockWatcher(node.URL, "", 3, "drain_timeout.txt", result, WatchTarget{OperationType: "transfer_native"})
+	to := common.Address{1}
+	tx, _ := types.SignNewTx(key, types.LatestSignerForChainID(config.ChainID), &types.LegacyTx{To: &to, Value: big.NewInt(1), Gas: params.TxGas, GasPrice: big.NewInt(2 * params.GWei)})
+	if err = client.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = waitMined(context.Background(), client, bundledErrors, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	confirmed := w.drain(0, start.Add(time.Second))
+	if elapsed := time.Since(start); elapsed < time.Second || elapsed > 5*time.Second {
+		t.Errorf("drain ended after %v, want the 1s timeout", elapsed)
+	}
+	w.wait()
+	result = completeResult(result, "transfer_native", "drain_timeout.txt", 3, confirmed, newFailureRecorder(), 0, nil)
+	if !result.DrainTimeout || result.Confirmed != 1 || result.Unconfirmed != 2 {
+		t.Errorf("drain timeout %v, %d confirmed, %d unconfirmed", result.DrainTimeout, result.Confirmed, result.Unconfirmed)
+	}
+}
@@ -10,91 +10,178 @@ import (
 	"log"
 	"math/big"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
 )
 
+// BenchmarkContext is the state of one run. Accounts, Contention and Verify are
+// the ones of its Runner: Accounts[id-1] receives the disjoint transaction id,
+// and the setup of a Verifier snapshots the state to verify when Verify is set.
 type BenchmarkContext struct {
 	Client          *ethclient.Client
+	Backend         *submitRouter
 	Chain           *bind.TransactOpts
 	Owner           common.Address
+	Senders         []common.Address
+	senders         []*sender
 	ContractAddress common.Address
+	Workload        string
+	Filename        string
 	Total           int
-	SendRate        int
+	Profile         LoadProfile
+	Accounts        []*ecdsa.PrivateKey
+	Contention      int
+	Verify          bool
 	Wait            sync.WaitGroup
-	FailCount       int
-	FailCountMutex  *sync.Mutex
-	NonceMutex      *sync.Mutex
+	Failures        *failureRecorder
 	MaxElapsed      float64
 	TotalElapsed    float64
+	Latencies       []float64
+	Receipts        map[int]*types.Receipt
+	Result          *Result
+	Sent            *atomic.Int64
+	Propagation     *propagationTracker
+	Mempool         *mempoolSampler
+	Replacements    *replacements
 	TotalMutex      *sync.Mutex
 	Ctx             context.Context
+	cancel          context.CancelFunc
+	watcher         *blockWatcher
+	runner          Runner
 }
 
-func initializeBenchmark(total int, sendRate int, operationType string, contractAddress common.Address) (*BenchmarkContext, string) {
-	client, err := ethclient.Dial(config.Host1)
+// newBenchmarkContext prepares a run of r.Total transactions sent by r.Key
+// with the settings of r.
+func newBenchmarkContext(ctx context.Context, client *ethclient.Client, r Runner, profile LoadProfile) (*BenchmarkContext, error) {
+	backend, err := newSubmitRouter(client, r.Endpoints, r.Strategy, r.Connections)
 	if err != nil {
-		log.Fatalf("client: %v", err)
+		return nil, err
 	}
+	ctx, cancel := context.WithCancel(ctx)
+	bc := &BenchmarkContext{
+		Client:       client,
+		Backend:      backend,
+		Total:        r.Total,
+		Profile:      profile,
+		Accounts:     r.Accounts,
+		Contention:   r.Contention,
+		Verify:       r.Verify,
+		Result:       &Result{},
+		Sent:         new(atomic.Int64),
+		Replacements: new(replacements),
+		Receipts:     make(map[int]*types.Receipt),
+		Failures:     newFailureRecorder(),
+		TotalMutex:   new(sync.Mutex),
+		Ctx:          ctx,
+		cancel:       cancel,
+		runner:       r,
+	}
+	bc.SendFrom(r.Key)
+	return bc, nil
+}
 
-	filename := fmt.Sprintf("%v.%v.%v.%v.%v.txt", config.Network, time.Now().Format("20060102_150405"), total, sendRate, operationType)
-	go CheckTpsByBlock(total, filename)
-	config.ChStart <- time.Now()
+// SendFrom spreads the transactions over the accounts of privateKeys in turn.
+// The first one becomes Owner, whose options Chain are the base of the
+// options of every transaction.
+func (bc *BenchmarkContext) SendFrom(privateKeys ...*ecdsa.PrivateKey) {
+	bc.senders, bc.Senders = nil, nil
+	for _, privateKey := range privateKeys {
+		s := newSender(bc.Backend, privateKey)
+		bc.senders = append(bc.senders, s)
+		bc.Senders = append(bc.Senders, s.address)
+	}
+	bc.Chain, bc.Owner = bc.senders[0].opts, bc.senders[0].address
+}
 
-	_, chain, owner := initialize(client, config.PrivateKey[0])
+// sender returns the sender of transaction index, the senders take turns.
+func (bc *BenchmarkContext) sender(index int) *sender {
+	return bc.senders[(index-1)%len(bc.senders)]
+}
 
-	return &BenchmarkContext{
-		Client:          client,
-		Chain:           chain,
-		Owner:           owner,
-		ContractAddress: contractAddress,
-		Total:           total,
-		SendRate:        sendRate,
-		FailCountMutex:  new(sync.Mutex),
-		NonceMutex:      new(sync.Mutex),
-		TotalMutex:      new(sync.Mutex),
-		Ctx:             context.Background(),
-	}, filename
+// start starts the block watcher of a run of operationType and the trackers
+// of the senders.
+func (bc *BenchmarkContext) start(operationType string) {
+	bc.Workload = operationType
+	bc.Filename = fmt.Sprintf("%v.%v.%v.%v.%v.txt", config.Network, time.Now().Format("20060102_150405"), bc.Total, bc.Profile.NominalRate(), operationType)
+	if bc.runner.Propagation {
+		bc.Propagation = newPropagationTracker(nodes(bc.runner.Endpoints))
+	}
+	observe, _ := bc.runner.endpoint(config.Endpoint.Observes)
+	target := WatchTarget{OperationType: operationType, Contract: bc.ContractAddress, Senders: bc.Senders, Sent: bc.Sent}
+	bc.watcher = startBlockWatcher(observe, bc.runner.CountMode, bc.Total, bc.Filename, bc.Result, target)
+	bc.Mempool = newMempoolSampler(bc.Client.Client(), bc.Senders, bc.runner.MempoolInterval, bc.runner.MempoolContent)
 }
 
-func (bc *BenchmarkContext) Benchmark(txFunc func(int) (*types.Transaction, error)) {
+// drainGrace is the least time the block watcher gets to count the blocks of
+// the last receipts after the senders are done.
+const drainGrace = 5 * time.Second
+
+func later(a time.Time, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// Benchmark runs a send phase at the load profile's rate, a drain phase that
+// waits up to the DrainTimeout of the Runner for the sent transactions to be mined and
+// counted, and finalizes the result. Transactions still pending when the drain
+// phase times out are reported as unconfirmed.
+func (bc *BenchmarkContext) Benchmark(send func(Tx) (*types.Transaction, error)) *Result {
+	log.Println("===== send =====")
+	second, sent := 0, 0
 	for i := 1; i <= bc.Total; i++ {
 		bc.Wait.Add(1)
 		go func(id int) {
 			defer bc.Wait.Done()
+			from := bc.sender(id)
+			attempts := 0
 			for {
-				bc.NonceMutex.Lock()
-				updateNonce(bc.Client, bc.Owner, bc.Chain)
-				bc.NonceMutex.Unlock()
-				config.Start = time.Now()
-				tx, err := txFunc(id)
+				next, err := from.tx(bc.Ctx, id)
+				submitted := time.Now()
+				var tx *types.Transaction
+				if err == nil {
+					tx, err = send(next)
+				}
+				if err != nil && bc.Ctx.Err() != nil {
+					return
+				}
 				if err != nil {
-					/*
-						Have to handle the Nonce Error case
-					*/
-					if err.Error() == "replacement transaction underpriced" || err.Error() == "already known" || err.Error() == "there is another tx which has the same nonce in the tx pool" {
-						updateNonce(bc.Client, bc.Owner, bc.Chain)
-						time.Sleep(time.Second)
+					// the nonce is unused or taken, the next one is fetched again
+					from.resync()
+					attempts++
+					if policy, ok := bc.Failures.retry(err, attempts); ok {
+						time.Sleep(policy.Backoff)
 						continue
-					} else {
-						bc.FailCountMutex.Lock()
-						bc.FailCount++
-						bc.FailCountMutex.Unlock()
-						return
 					}
+					bc.Failures.record(err)
+					return
+				}
+				bc.Sent.Add(1)
+				bc.Propagation.submit(tx.Hash(), submitted)
+				receipt, err := waitReplacing(bc.Ctx, bc.Backend, bc.Failures.errors, bc.Backend.SendTransaction, tx, from.key, bc.runner.Replacement, bc.Replacements)
+				if receipt != nil {
+					bc.TotalMutex.Lock()
+					bc.Receipts[id] = receipt
+					bc.TotalMutex.Unlock()
 				}
-				if !waitMined(bc.Ctx, bc.Client, tx) {
-					bc.FailCountMutex.Lock()
-					bc.FailCount++
-					bc.FailCountMutex.Unlock()
+				if err != nil {
+					// cancelled by the drain timeout, left unconfirmed
+					if bc.Ctx.Err() == nil {
+						bc.Failures.record(err)
+					}
 					return
 				}
-				elapsed := time.Since(config.Start).Seconds()
+				elapsed := time.Since(submitted).Seconds()
 				bc.TotalMutex.Lock()
 				bc.TotalElapsed += elapsed
+				bc.Latencies = append(bc.Latencies, elapsed)
 				if elapsed > bc.MaxElapsed {
 					bc.MaxElapsed = elapsed
 				}
@@ -103,28 +190,151 @@ func (bc *BenchmarkContext) Benchmark(txFunc func(int) (*types.Transaction, erro
 			}
 		}(i)
 
-		if i%bc.SendRate == 0 {
+		sent++
+		if sent >= bc.Profile.RateAt(second) {
 			log.Println("send ", i)
 			time.Sleep(time.Second)
+			second++
+			sent = 0
 		}
 	}
+	log.Printf("===== drain: up to %v =====\n", bc.runner.DrainTimeout)
+	deadline := time.Now().Add(bc.runner.DrainTimeout)
+	drain := time.AfterFunc(bc.runner.DrainTimeout, bc.cancel)
 	bc.Wait.Wait()
+	drain.Stop()
 	log.Println("max latency", bc.MaxElapsed)
-	config.ChFailedCount <- bc.FailCount
-	total := <-config.ChFinish
-	log.Println("avg latency:", bc.TotalElapsed/float64(total))
+	total := bc.watcher.drain(bc.Failures.Count(), later(deadline, time.Now().Add(drainGrace)))
+	bc.cancel()
+	log.Println("===== finalize =====")
+	avgLatency := 0.0
+	if total > 0 {
+		avgLatency = bc.TotalElapsed / float64(total)
+	}
+	log.Println("avg latency:", avgLatency)
+
+	bc.Result.Propagation = bc.Propagation.stop()
+	bc.Result.Mempool = bc.Mempool.stop()
+	bc.Replacements.record(bc.Result)
+	bc.Result.Endpoints = bc.Backend.stats()
+	bc.Result.Transport, bc.Result.Connections = bc.Backend.transports(), max(bc.runner.Connections, 1)
+	bc.Result.Fees = summarizeFees(bc.Receipts).log()
+	bc.Result.GasUsed = bc.Result.Fees.TotalGas
+	bc.Result.Contention = bc.Contention
+	if bc.Result.Duration > 0 {
+		bc.Result.GasPerSecond = float64(bc.Result.GasUsed) / bc.Result.Duration
+	}
+	log.Println("gas per second:", bc.Result.GasPerSecond)
+
+	return completeResult(bc.Result, bc.Workload, bc.Filename, bc.Total, total, bc.Failures, avgLatency, bc.Latencies)
+}
+
+// Succeeded returns the receipts of the transactions that were mined successfully.
+func (bc *BenchmarkContext) Succeeded() map[int]*types.Receipt {
+	receipts := make(map[int]*types.Receipt)
+	for id, receipt := range bc.Receipts {
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			receipts[id] = receipt
+		}
+	}
+	return receipts
+}
+
+// Deploy deploys a contract from Owner with an estimated gas limit and waits
+// until it is mined.
+func (bc *BenchmarkContext) Deploy(ctx context.Context, name string, deploy func(*bind.TransactOpts, bind.ContractBackend) (common.Address, *types.Transaction, error)) (common.Address, error) {
+	owner := bc.senders[0]
+	next, err := owner.tx(ctx, 0)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("deploy %v: %w", name, err)
+	}
+	next.Opts.GasLimit = 0
+	address, tx, err := deploy(next.Opts, bc.Client)
+	if err != nil {
+		owner.resync()
+		return common.Address{}, fmt.Errorf("deploy %v: %w", name, err)
+	}
+	if _, err = waitMined(ctx, bc.Backend, bc.Failures.errors, tx); err != nil {
+		return common.Address{}, fmt.Errorf("deploy %v: %w", name, err)
+	}
+	log.Printf("%v address: %s", name, address)
+	return address, nil
+}
+
+// Fund sends amount of native coin from Owner to every account and waits
+// until the transfers are mined.
+func (bc *BenchmarkContext) Fund(ctx context.Context, amount *big.Int, accounts ...common.Address) error {
+	owner := bc.senders[0]
+	gasPrice, err := bc.Backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("fund: %w", err)
+	}
+	var txs []*types.Transaction
+	for _, account := range accounts {
+		next, err := owner.tx(ctx, 0)
+		if err != nil {
+			return fmt.Errorf("fund %v: %w", account, err)
+		}
+		tx, err := next.Opts.Signer(owner.address, types.NewTx(&types.LegacyTx{
+			Nonce:    next.Nonce,
+			To:       &account,
+			Value:    amount,
+			Gas:      params.TxGas,
+			GasPrice: gasPrice,
+		}))
+		if err == nil {
+			err = bc.Client.SendTransaction(ctx, tx)
+		}
+		if err != nil {
+			owner.resync()
+			return fmt.Errorf("fund %v: %w", account, err)
+		}
+		txs = append(txs, tx)
+	}
+	for _, tx := range txs {
+		if _, err := waitMined(ctx, bc.Backend, bc.Failures.errors, tx); err != nil {
+			return fmt.Errorf("fund %v: %w", tx.To(), err)
+		}
+	}
+	return nil
+}
+
+// completeResult adds the sender side numbers to the result filled by the block watcher.
+func completeResult(result *Result, workload string, filename string, total int, confirmed int, failures *failureRecorder, avgLatency float64, latencies []float64) *Result {
+	result.Workload = workload
+	result.Total = total
+	result.Confirmed = confirmed
+	result.Failed = failures.Count()
+	result.Failures = failures.breakdown()
+	result.Unconfirmed = max(total-confirmed-result.Failed, 0)
+	if result.Unconfirmed > 0 {
+		log.Printf("%v transactions unconfirmed or lost\n", result.Unconfirmed)
+	}
+	result.AvgLatency = avgLatency
+	result.LatencyP50 = percentile(latencies, 50)
+	result.LatencyP95 = percentile(latencies, 95)
+	result.LatencyP99 = percentile(latencies, 99)
+	result.MaxLatency = percentile(latencies, 100)
+	result.BlockFile = filename
+	return result
 }
 
 func DeployContract(client *ethclient.Client, privateKey *ecdsa.PrivateKey) (common.Address, common.Address, common.Address) {
 	client, chain, owner := initialize(client, privateKey)
 	totalSupply := new(big.Int).Mul(config.OneEther, big.NewInt(1000000000))
 	ERC20Address, _, _, err := abi.DeployERC20(chain, client, totalSupply)
+	if err != nil {
+		log.Fatalln("failed to deploy ERC20", err)
+	}
 	updateNonce(client, owner, chain)
 	ERC721Address, _, _, err := abi.DeployERC721(chain, client)
+	if err != nil {
+		log.Fatalln("failed to deploy ERC721", err)
+	}
 	updateNonce(client, owner, chain)
 	ERC1155Address, _, _, err := abi.DeployERC1155(chain, client)
 	if err != nil {
-		log.Fatalln("failed to deploy", err)
+		log.Fatalln("failed to deploy ERC1155", err)
 	}
 	log.Printf("ERC20 Contract address: %s", ERC20Address)
 	log.Printf("ERC721 Contract address: %s", ERC721Address)
@@ -132,194 +342,487 @@ func DeployContract(client *ethclient.Client, privateKey *ecdsa.PrivateKey) (com
 	return ERC20Address, ERC721Address, ERC1155Address
 }
 
-func ERC20Mint(total int, sendRate int, contractAddress common.Address) {
-	bc, _ := initializeBenchmark(total, sendRate, "mint_erc20", contractAddress)
-	token, _ := abi.NewERC20(contractAddress, bc.Client)
-	mintAmount := new(big.Int).Mul(config.OneEther, big.NewInt(1))
+func ERC20Mint(total int, profile LoadProfile, contractAddress common.Address) *Result {
+	return run(NewRunner(total), profile, ERC20MintWorkload(contractAddress))
+}
 
-	txFunc := func(id int) (*types.Transaction, error) {
-		_, toAddress := GetKeyAndAddress(config.PrivateKeyHex[id-1])
-		return token.Mint(bc.Chain, toAddress, mintAmount)
+// ERC20MintWorkload mints one token to the recipient of every transaction.
+func ERC20MintWorkload(contractAddress common.Address) Workload {
+	var token *abi.ERC20
+	var expected balances
+	mintAmount := new(big.Int).Mul(config.OneEther, big.NewInt(1))
+	return &workload{
+		name: "mint_erc20",
+		setup: func(ctx context.Context, bc *BenchmarkContext) error {
+			bc.ContractAddress = contractAddress
+			token, _ = abi.NewERC20(contractAddress, bc.Backend)
+			if bc.Verify {
+				expected = snapshot(bc.recipientKeys(), erc20Balances(token))
+			}
+			return nil
+		},
+		transaction: func(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error) {
+			return token.Mint(tx.Opts, bc.recipient(tx.Index), mintAmount)
+		},
+		verify: func(ctx context.Context, bc *BenchmarkContext) *Verification {
+			if expected == nil {
+				return nil
+			}
+			for id := range bc.Succeeded() {
+				expected.add(bc.recipient(id), 0, mintAmount)
+			}
+			return verifyBalances("erc20_balance", expected, erc20Balances(token))
+		},
 	}
+}
 
-	bc.Benchmark(txFunc)
-	config.WaitSubscribeBlockHead.Wait()
+func ERC20Transfer(total int, profile LoadProfile, contractAddress common.Address) *Result {
+	return run(NewRunner(total), profile, ERC20TransferWorkload(contractAddress))
 }
 
-func ERC20Transfer(total int, sendRate int, contractAddress common.Address) {
-	bc, _ := initializeBenchmark(total, sendRate, "transfer_erc20", contractAddress)
-	token, _ := abi.NewERC20(contractAddress, bc.Client)
+// ERC20TransferWorkload transfers one token from the owner to the recipient of
+// every transaction.
+func ERC20TransferWorkload(contractAddress common.Address) Workload {
+	var token *abi.ERC20
+	var expected balances
 	Amount := new(big.Int).Mul(config.OneEther, big.NewInt(1))
-
-	txFunc := func(id int) (*types.Transaction, error) {
-		_, toAddress := GetKeyAndAddress(config.PrivateKeyHex[id-1])
-		return token.Transfer(bc.Chain, toAddress, Amount)
+	return &workload{
+		name: "transfer_erc20",
+		setup: func(ctx context.Context, bc *BenchmarkContext) error {
+			bc.ContractAddress = contractAddress
+			token, _ = abi.NewERC20(contractAddress, bc.Backend)
+			if bc.Verify {
+				expected = snapshot(append(bc.recipientKeys(), balanceKey{Holder: bc.Owner}), erc20Balances(token))
+			}
+			return nil
+		},
+		transaction: func(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error) {
+			return token.Transfer(tx.Opts, bc.recipient(tx.Index), Amount)
+		},
+		verify: func(ctx context.Context, bc *BenchmarkContext) *Verification {
+			if expected == nil {
+				return nil
+			}
+			for id := range bc.Succeeded() {
+				expected.add(bc.recipient(id), 0, Amount)
+				expected.add(bc.Owner, 0, new(big.Int).Neg(Amount))
+			}
+			return verifyBalances("erc20_balance", expected, erc20Balances(token))
+		},
 	}
-
-	bc.Benchmark(txFunc)
-	config.WaitSubscribeBlockHead.Wait()
 }
 
-func ERC721Mint(total int, sendRate int, contractAddress common.Address) {
-	bc, _ := initializeBenchmark(total, sendRate, "mint_erc721", contractAddress)
-	token, _ := abi.NewERC721(contractAddress, bc.Client)
+func ERC721Mint(total int, profile LoadProfile, contractAddress common.Address) *Result {
+	return run(NewRunner(total), profile, ERC721MintWorkload(contractAddress))
+}
 
-	txFunc := func(id int) (*types.Transaction, error) {
-		return token.Mint(bc.Chain, bc.Owner)
+// ERC721MintWorkload mints a new token to the owner with every transaction, or
+// to the hot address with contention.
+func ERC721MintWorkload(contractAddress common.Address) Workload {
+	var token *abi.ERC721
+	return &workload{
+		name: "mint_erc721",
+		setup: func(ctx context.Context, bc *BenchmarkContext) error {
+			bc.ContractAddress = contractAddress
+			token, _ = abi.NewERC721(contractAddress, bc.Backend)
+			return nil
+		},
+		transaction: func(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error) {
+			return token.Mint(tx.Opts, bc.mintRecipient(tx.Index))
+		},
+		verify: func(ctx context.Context, bc *BenchmarkContext) *Verification {
+			// the event tells the token id a mint created, its owner must be the recipient of the mint
+			owners := make(map[int64]common.Address)
+			for id, receipt := range bc.Succeeded() {
+				for _, l := range receipt.Logs {
+					if event, err := token.ParseTransfer(*l); err == nil && l.Address == contractAddress {
+						owners[event.TokenId.Int64()] = bc.mintRecipient(id)
+					}
+				}
+			}
+			return verifyERC721Owners(owners, erc721Owners(token))
+		},
 	}
-
-	bc.Benchmark(txFunc)
-	config.WaitSubscribeBlockHead.Wait()
 }
 
-func ERC721Transfer(total int, sendRate int, contractAddress common.Address) {
-	bc, _ := initializeBenchmark(total, sendRate, "transfer_erc721", contractAddress)
-	token, _ := abi.NewERC721(contractAddress, bc.Client)
+func ERC721Transfer(total int, profile LoadProfile, contractAddress common.Address) *Result {
+	return run(NewRunner(total), profile, ERC721TransferWorkload(contractAddress))
+}
 
-	txFunc := func(id int) (*types.Transaction, error) {
-		_, toAddress := GetKeyAndAddress(config.PrivateKeyHex[id-1])
-		return token.TransferFrom(bc.Chain, bc.Owner, toAddress, big.NewInt(int64(id)))
+// ERC721TransferWorkload transfers the token id of every transaction from the
+// owner to its recipient.
+func ERC721TransferWorkload(contractAddress common.Address) Workload {
+	var token *abi.ERC721
+	return &workload{
+		name: "transfer_erc721",
+		setup: func(ctx context.Context, bc *BenchmarkContext) error {
+			bc.ContractAddress = contractAddress
+			token, _ = abi.NewERC721(contractAddress, bc.Backend)
+			return nil
+		},
+		transaction: func(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error) {
+			return token.TransferFrom(tx.Opts, tx.Sender, bc.recipient(tx.Index), big.NewInt(int64(tx.Index)))
+		},
+		verify: func(ctx context.Context, bc *BenchmarkContext) *Verification {
+			owners := make(map[int64]common.Address)
+			for id := range bc.Succeeded() {
+				owners[int64(id)] = bc.recipient(id)
+			}
+			return verifyERC721Owners(owners, erc721Owners(token))
+		},
 	}
+}
 
-	bc.Benchmark(txFunc)
-	config.WaitSubscribeBlockHead.Wait()
+func ERC1155Mint(total int, profile LoadProfile, contractAddress common.Address) *Result {
+	return run(NewRunner(total), profile, ERC1155MintWorkload(contractAddress))
 }
 
-func ERC1155Mint(total int, sendRate int, contractAddress common.Address) {
-	bc, _ := initializeBenchmark(total, sendRate, "mint_erc1155", contractAddress)
-	token, _ := abi.NewERC1155(contractAddress, bc.Client)
+// ERC1155MintWorkload mints a new token id to the owner with every transaction,
+// or to the hot address with contention.
+func ERC1155MintWorkload(contractAddress common.Address) Workload {
+	var token *abi.ERC1155
 	Amount := new(big.Int).Mul(config.OneEther, big.NewInt(1))
-
-	txFunc := func(id int) (*types.Transaction, error) {
-		return token.Mint(bc.Chain, bc.Owner, Amount)
+	return &workload{
+		name: "mint_erc1155",
+		setup: func(ctx context.Context, bc *BenchmarkContext) error {
+			bc.ContractAddress = contractAddress
+			token, _ = abi.NewERC1155(contractAddress, bc.Backend)
+			return nil
+		},
+		transaction: func(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error) {
+			return token.Mint(tx.Opts, bc.mintRecipient(tx.Index), Amount)
+		},
+		verify: func(ctx context.Context, bc *BenchmarkContext) *Verification {
+			// every mint creates a new token id, so the expected balance starts from
+			// zero, and the event only tells the id
+			expected := make(balances)
+			for id, receipt := range bc.Succeeded() {
+				for _, l := range receipt.Logs {
+					if event, err := token.ParseTransferSingle(*l); err == nil && l.Address == contractAddress {
+						expected.add(bc.mintRecipient(id), event.Id.Int64(), Amount)
+					}
+				}
+			}
+			return verifyBalances("erc1155_balance", expected, erc1155Balances(token))
+		},
 	}
+}
 
-	bc.Benchmark(txFunc)
-	config.WaitSubscribeBlockHead.Wait()
+func ERC1155Transfer(total int, profile LoadProfile, contractAddress common.Address) *Result {
+	return run(NewRunner(total), profile, ERC1155TransferWorkload(contractAddress))
 }
 
-func ERC1155Transfer(total int, sendRate int, contractAddress common.Address) {
-	bc, _ := initializeBenchmark(total, sendRate, "transfer_erc1155", contractAddress)
-	token, _ := abi.NewERC1155(contractAddress, bc.Client)
+// ERC1155TransferWorkload transfers the token id of every transaction, or the
+// hot token with contention, from the owner to its recipient.
+func ERC1155TransferWorkload(contractAddress common.Address) Workload {
+	var token *abi.ERC1155
+	var expected balances
 	Amount := new(big.Int).Mul(config.OneEther, big.NewInt(1))
-
-	txFunc := func(id int) (*types.Transaction, error) {
-		_, toAddress := GetKeyAndAddress(config.PrivateKeyHex[id-1])
-		return token.SafeTransferFrom(bc.Chain, bc.Owner, toAddress, big.NewInt(int64(id)), Amount, nil)
+	return &workload{
+		name: "transfer_erc1155",
+		setup: func(ctx context.Context, bc *BenchmarkContext) error {
+			bc.ContractAddress = contractAddress
+			token, _ = abi.NewERC1155(contractAddress, bc.Backend)
+			if bc.Verify {
+				keys := make([]balanceKey, 0, 2*bc.Total)
+				for id := 1; id <= bc.Total; id++ {
+					tokenID, _ := bc.erc1155Transfer(id, Amount)
+					keys = append(keys, balanceKey{bc.recipient(id), tokenID}, balanceKey{bc.Owner, tokenID})
+				}
+				expected = snapshot(keys, erc1155Balances(token))
+			}
+			return nil
+		},
+		transaction: func(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error) {
+			tokenID, amount := bc.erc1155Transfer(tx.Index, Amount)
+			return token.SafeTransferFrom(tx.Opts, tx.Sender, bc.recipient(tx.Index), big.NewInt(tokenID), amount, nil)
+		},
+		verify: func(ctx context.Context, bc *BenchmarkContext) *Verification {
+			if expected == nil {
+				return nil
+			}
+			for id := range bc.Succeeded() {
+				tokenID, amount := bc.erc1155Transfer(id, Amount)
+				expected.add(bc.recipient(id), tokenID, amount)
+				expected.add(bc.Owner, tokenID, new(big.Int).Neg(amount))
+			}
+			return verifyBalances("erc1155_balance", expected, erc1155Balances(token))
+		},
 	}
+}
 
-	bc.Benchmark(txFunc)
-	config.WaitSubscribeBlockHead.Wait()
+func ERC1155BatchTransfer(total int, profile LoadProfile, contractAddress common.Address) *Result {
+	return run(NewRunner(total), profile, ERC1155BatchTransferWorkload(contractAddress))
 }
 
-func NativeTransfer(total int, sendRate int) {
-	bc, _ := initializeBenchmark(total, sendRate, "transfer_native", common.Address{})
-	transferAmount := new(big.Int).Mul(config.OneEther, big.NewInt(1))
+// ERC1155BatchTransferWorkload transfers config.BatchWidth token ids from the
+// owner to the recipient of every transaction.
+func ERC1155BatchTransferWorkload(contractAddress common.Address) Workload {
+	var token *abi.ERC1155
+	var expected balances
+	width := config.BatchWidth
+	Amount := big.NewInt(1)
+
+	// transaction id transfers the token ids (id-1)*width+1 .. id*width
+	batch := func(id int) ([]*big.Int, []*big.Int) {
+		ids := make([]*big.Int, width)
+		amounts := make([]*big.Int, width)
+		for k := 0; k < width; k++ {
+			ids[k] = big.NewInt(int64((id-1)*width + k + 1))
+			amounts[k] = Amount
+		}
+		return ids, amounts
+	}
+	return &workload{
+		name: "batchtransfer_erc1155",
+		setup: func(ctx context.Context, bc *BenchmarkContext) error {
+			bc.ContractAddress = contractAddress
+			token, _ = abi.NewERC1155(contractAddress, bc.Backend)
+			if bc.Verify {
+				keys := make([]balanceKey, 0, 2*bc.Total*width)
+				for id := 1; id <= bc.Total; id++ {
+					ids, _ := batch(id)
+					for _, tokenID := range ids {
+						keys = append(keys, balanceKey{bc.recipient(id), tokenID.Int64()}, balanceKey{bc.Owner, tokenID.Int64()})
+					}
+				}
+				expected = snapshot(keys, erc1155Balances(token))
+			}
+			return nil
+		},
+		transaction: func(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error) {
+			ids, amounts := batch(tx.Index)
+			return token.SafeBatchTransferFrom(tx.Opts, tx.Sender, bc.recipient(tx.Index), ids, amounts, nil)
+		},
+		verify: func(ctx context.Context, bc *BenchmarkContext) *Verification {
+			if expected == nil {
+				return nil
+			}
+			for id := range bc.Succeeded() {
+				ids, _ := batch(id)
+				for _, tokenID := range ids {
+					expected.add(bc.recipient(id), tokenID.Int64(), Amount)
+					expected.add(bc.Owner, tokenID.Int64(), new(big.Int).Neg(Amount))
+				}
+			}
+			return verifyBalances("erc1155_balance", expected, erc1155Balances(token))
+		},
+	}
+}
 
-	txFunc := func(id int) (*types.Transaction, error) {
-		_, toAddress := GetKeyAndAddress(config.PrivateKeyHex[id-1])
+func ERC20TransferFrom(total int, profile LoadProfile, contractAddress common.Address) *Result {
+	return run(NewRunner(total), profile, ERC20TransferFromWorkload(contractAddress))
+}
 
-		nonce, _ := bc.Client.PendingNonceAt(bc.Ctx, bc.Owner)
-		gasPrice, _ := bc.Client.SuggestGasPrice(bc.Ctx)
+// ERC20TransferFromWorkload approves the second account as spender of the
+// owner's tokens and benchmarks the spender's transferFrom calls. Teardown
+// revokes the allowance the failed calls left.
+func ERC20TransferFromWorkload(contractAddress common.Address) Workload {
+	var token, approval *abi.ERC20
+	var expected balances
+	var owner, spender common.Address
+	var ownerSender *sender
+	Amount := new(big.Int).Mul(config.OneEther, big.NewInt(1))
+	return &workload{
+		name: "transferfrom_erc20",
+		setup: func(ctx context.Context, bc *BenchmarkContext) error {
+			if len(bc.Accounts) < 2 {
+				return fmt.Errorf("transferFrom needs a second account as spender")
+			}
+			bc.ContractAddress = contractAddress
+			owner, ownerSender = bc.Owner, bc.senders[0]
+			spender = crypto.PubkeyToAddress(bc.Accounts[1].PublicKey)
+			approval, _ = abi.NewERC20(contractAddress, bc.Client)
+			tx, err := approval.Approve(bc.Chain, spender, new(big.Int).Mul(Amount, big.NewInt(int64(bc.Total))))
+			if err != nil {
+				return fmt.Errorf("approve spender: %w", err)
+			}
+			if _, err = waitMined(ctx, bc.Backend, bc.Failures.errors, tx); err != nil {
+				return fmt.Errorf("approve spender: %w", err)
+			}
+			bc.SendFrom(bc.Accounts[1])
 
-		tx := types.NewTx(&types.LegacyTx{
-			Nonce:    nonce,
-			To:       &toAddress,
-			Value:    transferAmount,
-			Gas:      config.GasLimit,
-			GasPrice: gasPrice,
-		})
+			token, _ = abi.NewERC20(contractAddress, bc.Backend)
+			if bc.Verify {
+				expected = snapshot(append(bc.recipientKeys(), balanceKey{Holder: owner}), erc20Balances(token))
+			}
+			return nil
+		},
+		transaction: func(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error) {
+			return token.TransferFrom(tx.Opts, owner, bc.recipient(tx.Index), Amount)
+		},
+		verify: func(ctx context.Context, bc *BenchmarkContext) *Verification {
+			if expected == nil {
+				return nil
+			}
+			for id := range bc.Succeeded() {
+				expected.add(bc.recipient(id), 0, Amount)
+				expected.add(owner, 0, new(big.Int).Neg(Amount))
+			}
+			return verifyBalances("erc20_balance", expected, erc20Balances(token))
+		},
+		teardown: func(ctx context.Context, bc *BenchmarkContext) error {
+			next, err := ownerSender.tx(ctx, 0)
+			if err != nil {
+				return fmt.Errorf("revoke spender: %w", err)
+			}
+			tx, err := approval.Approve(next.Opts, spender, new(big.Int))
+			if err != nil {
+				ownerSender.resync()
+				return fmt.Errorf("revoke spender: %w", err)
+			}
+			if _, err = waitMined(ctx, bc.Backend, bc.Failures.errors, tx); err != nil {
+				return fmt.Errorf("revoke spender: %w", err)
+			}
+			return nil
+		},
+	}
+}
 
-		signedTx, err := bc.Chain.Signer(bc.Owner, tx)
-		if err != nil {
-			return nil, err
-		}
+func ERC721SafeTransfer(total int, profile LoadProfile, contractAddress common.Address) *Result {
+	return run(NewRunner(total), profile, ERC721SafeTransferWorkload(contractAddress))
+}
 
-		return signedTx, bc.Client.SendTransaction(bc.Ctx, signedTx)
+// ERC721SafeTransferWorkload deploys a receiver contract and safe-transfers
+// the token ids 1..total to it.
+func ERC721SafeTransferWorkload(contractAddress common.Address) Workload {
+	var token *abi.ERC721
+	var receiver common.Address
+	return &workload{
+		name: "safetransfer_erc721",
+		setup: func(ctx context.Context, bc *BenchmarkContext) error {
+			var err error
+			receiver, err = bc.Deploy(ctx, "ERC721 receiver", func(chain *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, error) {
+				address, tx, _, err := abi.DeployERC721Receiver(chain, backend)
+				return address, tx, err
+			})
+			if err != nil {
+				return err
+			}
+			bc.ContractAddress = contractAddress
+			token, _ = abi.NewERC721(contractAddress, bc.Backend)
+			return nil
+		},
+		transaction: func(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error) {
+			return token.SafeTransferFrom(tx.Opts, tx.Sender, receiver, big.NewInt(int64(tx.Index)))
+		},
+		verify: func(ctx context.Context, bc *BenchmarkContext) *Verification {
+			owners := make(map[int64]common.Address)
+			for id := range bc.Succeeded() {
+				owners[int64(id)] = receiver
+			}
+			return verifyERC721Owners(owners, erc721Owners(token))
+		},
 	}
+}
 
-	bc.Benchmark(txFunc)
-	config.WaitSubscribeBlockHead.Wait()
+func NativeTransfer(total int, profile LoadProfile) *Result {
+	return run(NewRunner(total), profile, NativeTransferWorkload())
 }
 
-func MultiTransfer(total int) {
-	client, err := ethclient.Dial(config.Host1)
-	if err != nil {
-		log.Fatalf("client: %v", err)
+// NativeTransferWorkload sends one ether from the owner to the recipient of
+// every transaction.
+func NativeTransferWorkload() Workload {
+	var expected balances
+	transferAmount := new(big.Int).Mul(config.OneEther, big.NewInt(1))
+	return &workload{
+		name: "transfer_native",
+		setup: func(ctx context.Context, bc *BenchmarkContext) error {
+			if bc.Verify {
+				expected = snapshot(append(bc.recipientKeys(), balanceKey{Holder: bc.Owner}), nativeBalances(bc.Client))
+			}
+			return nil
+		},
+		transaction: func(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error) {
+			toAddress := bc.recipient(tx.Index)
+			gasPrice, err := bc.Backend.SuggestGasPrice(ctx)
+			if err != nil {
+				return nil, err
+			}
+			signedTx, err := tx.Opts.Signer(tx.Sender, types.NewTx(&types.LegacyTx{
+				Nonce:    tx.Nonce,
+				To:       &toAddress,
+				Value:    transferAmount,
+				Gas:      config.GasLimit,
+				GasPrice: gasPrice,
+			}))
+			if err != nil {
+				return nil, err
+			}
+			return signedTx, bc.Backend.SendTransaction(ctx, signedTx)
+		},
+		verify: func(ctx context.Context, bc *BenchmarkContext) *Verification {
+			if expected == nil {
+				return nil
+			}
+			for _, receipt := range bc.Receipts {
+				expected.add(bc.Owner, 0, new(big.Int).Neg(fee(receipt)))
+			}
+			for id := range bc.Succeeded() {
+				expected.add(bc.recipient(id), 0, transferAmount)
+				expected.add(bc.Owner, 0, new(big.Int).Neg(transferAmount))
+			}
+			return verifyBalances("native_balance", expected, nativeBalances(bc.Client))
+		},
 	}
-	privateKeys := config.PrivateKey[:config.Multi]
-	filename := fmt.Sprintf("%v.%v.%v.%v.txt", config.Network, time.Now().Format("20060102_150405"), total, "transfer_multi")
-	go CheckTpsByBlock(total, filename)
-	config.ChStart <- time.Now()
+}
 
+func MultiTransfer(total int, profile LoadProfile) *Result {
+	return run(NewRunner(total), profile, MultiTransferWorkload())
+}
+
+// MultiTransferWorkload spreads the transactions over the first config.Multi
+// accounts of the run in turn, every account sending 0.1 ether to itself. The
+// owner funds the senders that cannot pay for their share of the run.
+func MultiTransferWorkload() Workload {
 	transferAmount := new(big.Int).SetInt64(100000000000000000) // 0.1ETH
-	var Wait sync.WaitGroup
-	failCount := 0
-	failCountMutex := new(sync.Mutex)
-	nonceMutex := new(sync.Mutex)
-	maxElapsed := 0.0
-	totalElapsed := 0.0
-	totalMutex := new(sync.Mutex)
-	ctx := context.Background()
-	txsPerAccount := total / len(privateKeys)
-
-	for i, privateKey := range privateKeys {
-		Wait.Add(1)
-		go func(pk *ecdsa.PrivateKey, id int) {
-			defer Wait.Done()
-			_, chain, owner := initialize(client, pk)
-			_, toAddress := GetKeyAndAddress(config.PrivateKeyHex[id])
-
-			for j := 0; j < txsPerAccount; j++ {
-				nonceMutex.Lock()
-				updateNonce(client, owner, chain)
-				nonce, _ := client.PendingNonceAt(ctx, owner)
-				nonceMutex.Unlock()
-
-				gasPrice, _ := client.SuggestGasPrice(ctx)
-				nativeTx := types.NewTx(&types.LegacyTx{
-					Nonce:    nonce,
-					To:       &toAddress,
-					Value:    transferAmount,
-					Gas:      config.GasLimit,
-					GasPrice: gasPrice,
-				})
-
-				signedTx, _ := types.SignTx(nativeTx, types.NewEIP155Signer(config.ChainID), pk)
-				config.Err = client.SendTransaction(ctx, signedTx)
-				if config.Err != nil {
-					if config.Err.Error() == "replacement transaction underpriced" {
-						time.Sleep(time.Second)
-						updateNonce(client, owner, chain)
-						continue
-					} else {
-						failCountMutex.Lock()
-						failCount++
-						failCountMutex.Unlock()
-						return
-					}
-				}
-				config.Start = time.Now()
-				if !waitMined(ctx, client, signedTx) {
-					failCountMutex.Lock()
-					failCount++
-					failCountMutex.Unlock()
-					return
+	multi := config.Multi
+	return &workload{
+		name: "transfer_multi",
+		setup: func(ctx context.Context, bc *BenchmarkContext) error {
+			if multi < 1 || multi > len(bc.Accounts) {
+				return fmt.Errorf("%d senders requested, %d accounts loaded", multi, len(bc.Accounts))
+			}
+			gasPrice, err := bc.Backend.SuggestGasPrice(ctx)
+			if err != nil {
+				return err
+			}
+			share := uint64((bc.Total + multi - 1) / multi)
+			need := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(share*config.GasLimit))
+			need.Add(need, transferAmount)
+			var short []common.Address
+			for _, key := range bc.Accounts[:multi] {
+				account := crypto.PubkeyToAddress(key.PublicKey)
+				balance, err := bc.Client.BalanceAt(ctx, account, nil)
+				if err != nil {
+					return err
 				}
-				elapsed := time.Since(config.Start).Seconds()
-				totalMutex.Lock()
-				totalElapsed += elapsed
-				totalMutex.Unlock()
-				if elapsed > maxElapsed {
-					maxElapsed = elapsed
+				if balance.Cmp(need) < 0 {
+					short = append(short, account)
 				}
 			}
-		}(privateKey, i)
+			if err = bc.Fund(ctx, need, short...); err != nil {
+				return err
+			}
+			bc.SendFrom(bc.Accounts[:multi]...)
+			return nil
+		},
+		transaction: func(ctx context.Context, bc *BenchmarkContext, tx Tx) (*types.Transaction, error) {
+			gasPrice, err := bc.Backend.SuggestGasPrice(ctx)
+			if err != nil {
+				return nil, err
+			}
+			signedTx, err := tx.Opts.Signer(tx.Sender, types.NewTx(&types.LegacyTx{
+				Nonce:    tx.Nonce,
+				To:       &tx.Sender,
+				Value:    transferAmount,
+				Gas:      config.GasLimit,
+				GasPrice: gasPrice,
+			}))
+			if err != nil {
+				return nil, err
+			}
+			return signedTx, bc.Backend.SendTransaction(ctx, signedTx)
+		},
 	}
-	Wait.Wait()
-	log.Println("max latency", maxElapsed)
-	config.ChFailedCount <- failCount
-	total2 := <-config.ChFinish
-	log.Println("avg latency:", totalElapsed/float64(total2))
-	config.WaitSubscribeBlockHead.Wait()
 }
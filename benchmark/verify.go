@@ -0,0 +1,201 @@
+package benchmark
+
+import (
+	"context"
+	"log"
+	"math/big"
+
+	"decipher.com/tps/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Verification compares the on-chain state after a run with the state expected
+// from the confirmed transactions. Mismatches are correctness failures and are
+// not counted in Result.Failed.
+type Verification struct {
+	Checked    int        `json:"checked"`
+	Mismatches []Mismatch `json:"mismatches,omitempty"`
+}
+
+type Mismatch struct {
+	Kind     string `json:"kind"`
+	Subject  string `json:"subject"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// balanceKey identifies a balance. ID is only used by ERC1155.
+type balanceKey struct {
+	Holder common.Address
+	ID     int64
+}
+
+type balances map[balanceKey]*big.Int
+
+func (b balances) add(holder common.Address, id int64, delta *big.Int) {
+	key := balanceKey{holder, id}
+	if _, ok := b[key]; !ok {
+		b[key] = new(big.Int)
+	}
+	b[key].Add(b[key], delta)
+}
+
+func (b balances) keys() []balanceKey {
+	keys := make([]balanceKey, 0, len(b))
+	for k := range b {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// balanceFetcher reads the current balances of keys, in order.
+type balanceFetcher func(keys []balanceKey) ([]*big.Int, error)
+
+func erc20Balances(token *abi.ERC20) balanceFetcher {
+	return func(keys []balanceKey) ([]*big.Int, error) {
+		values := make([]*big.Int, len(keys))
+		for i, k := range keys {
+			balance, err := token.BalanceOf(nil, k.Holder)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = balance
+		}
+		return values, nil
+	}
+}
+
+func erc1155Balances(token *abi.ERC1155) balanceFetcher {
+	return func(keys []balanceKey) ([]*big.Int, error) {
+		const batch = 200
+		values := make([]*big.Int, 0, len(keys))
+		for start := 0; start < len(keys); start += batch {
+			end := min(start+batch, len(keys))
+			accounts := make([]common.Address, 0, end-start)
+			ids := make([]*big.Int, 0, end-start)
+			for _, k := range keys[start:end] {
+				accounts = append(accounts, k.Holder)
+				ids = append(ids, big.NewInt(k.ID))
+			}
+			batchValues, err := token.BalanceOfBatch(nil, accounts, ids)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, batchValues...)
+		}
+		return values, nil
+	}
+}
+
+func nativeBalances(client *ethclient.Client) balanceFetcher {
+	return func(keys []balanceKey) ([]*big.Int, error) {
+		values := make([]*big.Int, len(keys))
+		for i, k := range keys {
+			balance, err := client.BalanceAt(context.Background(), k.Holder, nil)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = balance
+		}
+		return values, nil
+	}
+}
+
+// snapshot returns the current balances of keys to apply the expected changes to.
+func snapshot(keys []balanceKey, fetch balanceFetcher) balances {
+	values, err := fetch(keys)
+	if err != nil {
+		log.Println("failed to snapshot balances:", err)
+		return nil
+	}
+	b := make(balances, len(keys))
+	for i, k := range keys {
+		b[k] = values[i]
+	}
+	return b
+}
+
+func verifyBalances(kind string, expected balances, fetch balanceFetcher) *Verification {
+	keys := expected.keys()
+	values, err := fetch(keys)
+	if err != nil {
+		log.Println("failed to verify balances:", err)
+		return nil
+	}
+	v := &Verification{Checked: len(keys)}
+	for i, k := range keys {
+		if values[i].Cmp(expected[k]) != 0 {
+			subject := k.Holder.Hex()
+			if kind == "erc1155_balance" {
+				subject = subject + "/" + big.NewInt(k.ID).String()
+			}
+			v.Mismatches = append(v.Mismatches, Mismatch{kind, subject, expected[k].String(), values[i].String()})
+		}
+	}
+	return v.log()
+}
+
+// ownerFetcher reads the current owner of an ERC721 token id.
+type ownerFetcher func(id int64) (common.Address, error)
+
+func erc721Owners(token *abi.ERC721) ownerFetcher {
+	return func(id int64) (common.Address, error) {
+		return token.OwnerOf(nil, big.NewInt(id))
+	}
+}
+
+func verifyERC721Owners(owners map[int64]common.Address, fetch ownerFetcher) *Verification {
+	v := &Verification{Checked: len(owners)}
+	for id, expected := range owners {
+		owner, err := fetch(id)
+		if err != nil {
+			v.Mismatches = append(v.Mismatches, Mismatch{"erc721_owner", big.NewInt(id).String(), expected.Hex(), err.Error()})
+			continue
+		}
+		if owner != expected {
+			v.Mismatches = append(v.Mismatches, Mismatch{"erc721_owner", big.NewInt(id).String(), expected.Hex(), owner.Hex()})
+		}
+	}
+	return v.log()
+}
+
+func (v *Verification) log() *Verification {
+	log.Printf("verified %v values, %v correctness failures\n", v.Checked, len(v.Mismatches))
+	for i, m := range v.Mismatches {
+		if i == 10 {
+			log.Printf("... %v more\n", len(v.Mismatches)-i)
+			break
+		}
+		log.Printf("%v %v: expected %v, actual %v\n", m.Kind, m.Subject, m.Expected, m.Actual)
+	}
+	return v
+}
+
+// recipientKeys returns the balance keys of the recipients of the transactions 1..Total.
+func (bc *BenchmarkContext) recipientKeys() []balanceKey {
+	keys := make([]balanceKey, 0, bc.Total)
+	for id := 1; id <= bc.Total; id++ {
+		keys = append(keys, balanceKey{Holder: bc.recipient(id)})
+	}
+	return keys
+}
+
+// recipient returns the receiving account of transaction id, the account id of
+// Accounts or the shared hotAddress if the transaction is routed to the hot state.
+func (bc *BenchmarkContext) recipient(id int) common.Address {
+	if bc.isHot(id) {
+		return hotAddress
+	}
+	return crypto.PubkeyToAddress(bc.Accounts[id-1].PublicKey)
+}
+
+// fee returns the native amount paid for the receipt.
+func fee(receipt *types.Receipt) *big.Int {
+	if receipt.EffectiveGasPrice == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+}
@@ -0,0 +1,118 @@
+package benchmark
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fakeBalances returns the balances of chain, zero for unknown keys.
+func fakeBalances(chain balances) balanceFetcher {
+	return func(keys []balanceKey) ([]*big.Int, error) {
+		values := make([]*big.Int, len(keys))
+		for i, k := range keys {
+			values[i] = new(big.Int)
+			if balance, ok := chain[k]; ok {
+				values[i].Set(balance)
+			}
+		}
+		return values, nil
+	}
+}
+
+func TestBalancesAdd(t *testing.T) {
+	alice, bob := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	b := make(balances)
+	b.add(alice, 0, big.NewInt(5))
+	b.add(alice, 0, big.NewInt(-2))
+	b.add(alice, 1, big.NewInt(7))
+	b.add(bob, 0, big.NewInt(1))
+
+	for key, want := range map[balanceKey]int64{{alice, 0}: 3, {alice, 1}: 7, {bob, 0}: 1} {
+		if got := b[key]; got == nil || got.Int64() != want {
+			t.Errorf("%v = %v, want %v", key, got, want)
+		}
+	}
+	if len(b.keys()) != 3 {
+		t.Errorf("keys = %v", b.keys())
+	}
+}
+
+func TestVerifyBalances(t *testing.T) {
+	alice, bob := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	chain := balances{{alice, 0}: big.NewInt(100), {bob, 0}: big.NewInt(10)}
+
+	// a transfer of 30 from alice to bob applied to the snapshot
+	expected := snapshot([]balanceKey{{alice, 0}, {bob, 0}}, fakeBalances(chain))
+	if expected[balanceKey{alice, 0}].Int64() != 100 || expected[balanceKey{bob, 0}].Int64() != 10 {
+		t.Fatalf("snapshot = %v", expected)
+	}
+	expected.add(alice, 0, big.NewInt(-30))
+	expected.add(bob, 0, big.NewInt(30))
+
+	chain.add(alice, 0, big.NewInt(-30))
+	chain.add(bob, 0, big.NewInt(30))
+	if v := verifyBalances("erc20_balance", expected, fakeBalances(chain)); v.Checked != 2 || len(v.Mismatches) != 0 {
+		t.Errorf("matching balances = %+v", v)
+	}
+
+	chain.add(bob, 0, big.NewInt(-1))
+	v := verifyBalances("erc20_balance", expected, fakeBalances(chain))
+	want := Mismatch{"erc20_balance", bob.Hex(), "40", "39"}
+	if v.Checked != 2 || len(v.Mismatches) != 1 || v.Mismatches[0] != want {
+		t.Errorf("mismatching balances = %+v, want %+v", v, want)
+	}
+
+	failing := func(keys []balanceKey) ([]*big.Int, error) { return nil, errors.New("unavailable") }
+	if b := snapshot([]balanceKey{{alice, 0}}, failing); b != nil {
+		t.Errorf("failed snapshot = %v", b)
+	}
+	if v := verifyBalances("erc20_balance", expected, failing); v != nil {
+		t.Errorf("failed verification = %+v", v)
+	}
+}
+
+func TestVerifyERC1155Subject(t *testing.T) {
+	alice := common.HexToAddress("0x01")
+	expected := balances{{alice, 7}: big.NewInt(2)}
+	v := verifyBalances("erc1155_balance", expected, fakeBalances(balances{}))
+	if len(v.Mismatches) != 1 || v.Mismatches[0].Subject != alice.Hex()+"/7" {
+		t.Errorf("mismatches = %+v", v.Mismatches)
+	}
+}
+
+func TestVerifyERC721Owners(t *testing.T) {
+	alice, bob := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	chain := map[int64]common.Address{1: alice, 2: bob, 3: alice}
+	fetch := func(id int64) (common.Address, error) {
+		owner, ok := chain[id]
+		if !ok {
+			return common.Address{}, errors.New("invalid token ID")
+		}
+		return owner, nil
+	}
+
+	if v := verifyERC721Owners(map[int64]common.Address{1: alice, 2: bob}, fetch); v.Checked != 2 || len(v.Mismatches) != 0 {
+		t.Errorf("matching owners = %+v", v)
+	}
+	v := verifyERC721Owners(map[int64]common.Address{2: bob, 3: bob, 4: alice}, fetch)
+	if v.Checked != 3 || len(v.Mismatches) != 2 {
+		t.Fatalf("mismatching owners = %+v", v)
+	}
+	for _, m := range v.Mismatches {
+		switch m.Subject {
+		case "3":
+			if m.Expected != bob.Hex() || m.Actual != alice.Hex() {
+				t.Errorf("token 3: %+v", m)
+			}
+		case "4":
+			if m.Actual != "invalid token ID" {
+				t.Errorf("token 4: %+v", m)
+			}
+		default:
+			t.Errorf("unexpected mismatch %+v", m)
+		}
+	}
+}
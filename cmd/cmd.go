@@ -5,6 +5,8 @@ import (
 	"decipher.com/tps/config"
 	"github.com/spf13/cobra"
 	"log"
+	"strings"
+	"time"
 )
 
 var rootCmd = &cobra.Command{
@@ -15,110 +17,195 @@ var rootCmd = &cobra.Command{
 
 func Execute() {
 	config.LoadAddresses("config/config.yml")
+	addWorkloadCommands()
 	if err := rootCmd.Execute(); err != nil {
 		log.Printf("Execute err: %v", err)
 	}
 }
 
+var repeatOptions benchmark.RepeatOptions
+var contention int
+var contentionSweep []int
+var submitStrategy string
+var connections int
+var mempoolInterval time.Duration
+var mempoolContent bool
+var replacement benchmark.ReplacementPolicy
+var drainTimeout time.Duration
+var verify bool
+var propagation bool
+
 func init() {
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(updateConfig)
-	rootCmd.AddCommand(erc20MintCmd)
-	rootCmd.AddCommand(erc20TransferCmd)
-	rootCmd.AddCommand(erc721MintCmd)
-	rootCmd.AddCommand(erc721TransferCmd)
-	rootCmd.AddCommand(erc1155MintCmd)
-	rootCmd.AddCommand(erc1155TransferCmd)
-	rootCmd.AddCommand(nativeTransferCmd)
-	rootCmd.AddCommand(multiTransferCmd)
-}
+	rootCmd.AddCommand(rpcBenchCmd)
+	rootCmd.AddCommand(runCmd)
 
-var initCmd = &cobra.Command{
-	Use:   "init",
-	Short: "Initialize contracts",
-	Run: func(cmd *cobra.Command, args []string) {
-		benchmark.InitAccount(1)
-		ERC20, ERC721, ERC1155 := benchmark.InitContract()
-		benchmark.UpdateAddress(ERC20, ERC721, ERC1155)
-	},
+	rpcBenchCmd.Flags().StringSliceVar(&rpcBenchOptions.Methods, "methods", nil, "read methods to call (default all)")
+	rpcBenchCmd.Flags().IntVar(&rpcBenchOptions.Rate, "rate", 100, "requests per second over all methods")
+	rpcBenchCmd.Flags().IntVar(&rpcBenchOptions.Duration, "duration", 30, "seconds to generate load")
+	rpcBenchCmd.Flags().IntVar(&rpcBenchOptions.Concurrency, "concurrency", 16, "number of concurrent requests")
+	rpcBenchCmd.Flags().IntVar(&rpcBenchOptions.LogRange, "log-range", 100, "number of blocks queried by eth_getLogs")
+	rpcBenchCmd.Flags().IntVar(&rpcBenchOptions.TokenRange, "token-range", 0, "ERC721 and ERC1155 token ids to read (default condition.total)")
+	rpcBenchCmd.Flags().StringVar(&rpcBenchOptions.Write, "write", "", "benchmark workload to run at the same time, e.g. erc20transfer")
 }
 
-var updateConfig = &cobra.Command{
-	Use:   "updateConfig",
-	Short: "Update Network Config",
-	Run: func(cmd *cobra.Command, args []string) {
-		benchmark.UpdateConfig(args[0])
-	},
+// addWorkloadCommands adds a command for every registered workload, including
+// the ones registered by the packages of another module's main package.
+func addWorkloadCommands() {
+	for _, definition := range benchmark.Definitions() {
+		rootCmd.AddCommand(workloadCommand(definition))
+	}
 }
 
-var erc20MintCmd = &cobra.Command{
-	Use:   "erc20mint",
-	Short: "Mint ERC20 tokens",
-	Run: func(cmd *cobra.Command, args []string) {
-		benchmark.InitAccount(config.Total)
-		benchmark.ERC20Mint(config.Total, config.Rate, config.ERC20ADDRESS)
-	},
+func workloadCommand(definition benchmark.Definition) *cobra.Command {
+	c := &cobra.Command{
+		Use:   definition.Name,
+		Short: definition.Short,
+		Long:  definition.Long,
+		Run: func(cmd *cobra.Command, args []string) {
+			benchmark.InitAccount(definition.AccountsFor(config.Total))
+			runTrials(cmd, func(runner *benchmark.Runner) *benchmark.Result {
+				return definition.Run(runner, benchmark.ConstantRate(config.Rate))
+			})
+		},
+	}
+	c.Flags().IntVar(&repeatOptions.Trials, "repeat", 1, "number of trials of the same benchmark")
+	c.Flags().DurationVar(&repeatOptions.Cooldown, "cooldown", 0, "wait time between trials")
+	c.Flags().StringVar(&repeatOptions.Reset, "reset", "", "shell command that resets the chain state between trials")
+	c.Flags().StringVar(&submitStrategy, "strategy", "", "submit endpoint of each transaction: round-robin, sticky, random or all-to-one (default strategy.value)")
+	c.Flags().IntVar(&connections, "connections", 0, "client connections per submit endpoint (default connections.value)")
+	c.Flags().DurationVar(&mempoolInterval, "mempool-interval", 0, "interval of the mempool samples, 0 disables them (default condition.mempoolInterval ms)")
+	c.Flags().BoolVar(&mempoolContent, "mempool-content", false, "follow the benchmark transactions in txpool_inspect or txpool_content (default condition.mempoolContent)")
+	c.Flags().DurationVar(&replacement.After, "replace-after", 0, "resend a transaction not mined after this time with a bumped fee (default condition.replaceAfter s)")
+	c.Flags().IntVar(&replacement.Bump, "fee-bump", 0, "fee increase in percent of a replacement (default condition.feeBump)")
+	c.Flags().IntVar(&replacement.MaxAttempts, "max-replacements", 0, "replacements per transaction (default condition.maxReplacements)")
+	c.Flags().DurationVar(&drainTimeout, "drain-timeout", 0, "max wait for the sent transactions after sending (default condition.drainTimeout s)")
+	if definition.SupportsVerify {
+		c.Flags().BoolVar(&verify, "verify", false, "verify the on-chain state after the run")
+	}
+	c.Flags().BoolVar(&propagation, "propagation", false, "measure the propagation of the transactions to the pools of every node")
+	if definition.SupportsContention {
+		c.Flags().IntVar(&contention, "contention", 0, "percentage of transactions routed to a shared hot recipient or token id (default condition.contention)")
+		c.Flags().IntSliceVar(&contentionSweep, "contention-sweep", nil, "run at each contention level, e.g. 0,25,50,75,100")
+	}
+	if definition.Flags != nil {
+		definition.Flags(c.Flags())
+	}
+	for _, required := range definition.Required {
+		if len(required) == 1 {
+			c.MarkFlagRequired(required[0])
+		} else {
+			c.MarkFlagsOneRequired(required...)
+		}
+	}
+	return c
 }
 
-var erc20TransferCmd = &cobra.Command{
-	Use:   "erc20transfer",
-	Short: "Transfer ERC20 tokens",
-	Run: func(cmd *cobra.Command, args []string) {
-		benchmark.InitAccount(config.Total)
-		benchmark.ERC20Transfer(config.Total, config.Rate, config.ERC20ADDRESS)
-	},
+// newRunner returns the configured Runner of a trial with the options given
+// on the command line.
+func newRunner(cmd *cobra.Command) *benchmark.Runner {
+	runner := benchmark.NewRunner(config.Total)
+	if cmd.Flags().Changed("contention") {
+		runner.Contention = contention
+	}
+	if cmd.Flags().Changed("verify") {
+		runner.Verify = verify
+	}
+	if cmd.Flags().Changed("propagation") {
+		runner.Propagation = propagation
+	}
+	if submitStrategy != "" {
+		runner.Strategy = submitStrategy
+	}
+	if connections > 0 {
+		runner.Connections = connections
+	}
+	if cmd.Flags().Changed("mempool-interval") {
+		runner.MempoolInterval = max(mempoolInterval, 0)
+	}
+	if cmd.Flags().Changed("mempool-content") {
+		runner.MempoolContent = mempoolContent
+	}
+	if replacement.After > 0 {
+		runner.Replacement.After = replacement.After
+	}
+	if replacement.Bump > 0 {
+		runner.Replacement.Bump = replacement.Bump
+	}
+	if replacement.MaxAttempts > 0 {
+		runner.Replacement.MaxAttempts = replacement.MaxAttempts
+	}
+	if drainTimeout > 0 {
+		runner.DrainTimeout = drainTimeout
+	}
+	return runner
 }
 
-var erc721MintCmd = &cobra.Command{
-	Use:   "erc721mint",
-	Short: "Mint ERC721 tokens",
-	Run: func(cmd *cobra.Command, args []string) {
-		benchmark.InitAccount(config.Total)
-		benchmark.ERC721Mint(config.Total, config.Rate, config.ERC721ADDRESS)
-	},
+// runTrials runs the benchmark once, or repeatedly with aggregated statistics when
+// --repeat is set. --contention-sweep runs it at every contention level.
+func runTrials(cmd *cobra.Command, run func(runner *benchmark.Runner) *benchmark.Result) {
+	if len(contentionSweep) > 0 {
+		benchmark.SweepContention(contentionSweep, repeatOptions, func(contention int) *benchmark.Result {
+			runner := newRunner(cmd)
+			runner.Contention = contention
+			return run(runner)
+		})
+		return
+	}
+	trial := func() *benchmark.Result {
+		return run(newRunner(cmd))
+	}
+	if repeatOptions.Trials <= 1 {
+		benchmark.StoreResult(trial())
+		return
+	}
+	benchmark.Repeat(repeatOptions, trial)
 }
 
-var erc721TransferCmd = &cobra.Command{
-	Use:   "erc721transfer",
-	Short: "Transfer ERC721 tokens",
-	Run: func(cmd *cobra.Command, args []string) {
-		benchmark.InitAccount(config.Total)
-		benchmark.ERC721Transfer(config.Total, config.Rate, config.ERC721ADDRESS)
-	},
-}
+var rpcBenchOptions benchmark.RPCBenchOptions
 
-var erc1155MintCmd = &cobra.Command{
-	Use:   "erc1155mint",
-	Short: "Mint ERC1155 tokens",
+var rpcBenchCmd = &cobra.Command{
+	Use:   "rpcbench",
+	Short: "Benchmark read RPC methods",
+	Long: `Benchmark read RPC methods at a target rate and report the QPS,
+error rate and latency percentiles per method. Methods: ` + strings.Join(benchmark.RPCMethods, ", "),
 	Run: func(cmd *cobra.Command, args []string) {
-		benchmark.InitAccount(config.Total)
-		benchmark.ERC1155Mint(config.Total, config.Rate, config.ERC1155ADDRESS)
+		benchmark.InitAccount(max(config.Total, 2))
+		if rpcBenchOptions.TokenRange == 0 {
+			rpcBenchOptions.TokenRange = config.Total
+		}
+		benchmark.RPCBench(rpcBenchOptions)
 	},
 }
 
-var erc1155TransferCmd = &cobra.Command{
-	Use:   "erc1155transfer",
-	Short: "Transfer ERC1155 tokens",
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Initialize contracts",
 	Run: func(cmd *cobra.Command, args []string) {
-		benchmark.InitAccount(config.Total)
-		benchmark.ERC1155Transfer(config.Total, config.Rate, config.ERC1155ADDRESS)
+		benchmark.InitAccount(1)
+		ERC20, ERC721, ERC1155 := benchmark.InitContract()
+		benchmark.UpdateAddress(ERC20, ERC721, ERC1155)
 	},
 }
 
-var nativeTransferCmd = &cobra.Command{
-	Use:   "nativetransfer",
-	Short: "Transfer Native Coins",
+var updateConfig = &cobra.Command{
+	Use:   "updateConfig",
+	Short: "Update Network Config",
 	Run: func(cmd *cobra.Command, args []string) {
-		benchmark.InitAccount(config.Total)
-		benchmark.NativeTransfer(config.Total, config.Rate)
+		benchmark.UpdateConfig(args[0])
 	},
 }
 
-var multiTransferCmd = &cobra.Command{
-	Use:   "multitransfer",
-	Short: "Transfer Native Coins ",
+var runCmd = &cobra.Command{
+	Use:   "run [scenario.yml]",
+	Short: "Run the phases of a scenario file in order",
+	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		benchmark.InitAccount(config.Total)
-		benchmark.MultiTransfer(config.Total)
+		scenario, err := benchmark.LoadScenario(args[0])
+		if err != nil {
+			log.Fatalf("Failed to load scenario: %v", err)
+		}
+		benchmark.RunScenario(scenario)
 	},
 }
@@ -8,6 +8,8 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
 var ChainID = big.NewInt(8216)
@@ -16,6 +18,17 @@ var Host2 = "ws://127.0.0.1:9552"
 var Network = "klay"
 var config Config
 
+// Endpoint is a node antps connects to. Role is "submit", "observe" or "both".
+// Transport is "ws", "http" or "ipc" and taken from the URL when empty.
+type Endpoint struct {
+	URL       string `yaml:"url"`
+	Role      string `yaml:"role"`
+	Transport string `yaml:"transport"`
+}
+
+func (e Endpoint) Submits() bool  { return e.Role == "submit" || e.Role == "both" }
+func (e Endpoint) Observes() bool { return e.Role == "observe" || e.Role == "both" }
+
 type Config struct {
 	Contracts struct {
 		ERC20 struct {
@@ -38,10 +51,56 @@ type Config struct {
 		GasLimit struct {
 			Value uint64 `yaml:"value"`
 		} `yaml:"gasLimit"`
+		WarmUp struct {
+			Value int `yaml:"value"`
+		} `yaml:"warmUp"`
+		CoolDown struct {
+			Value int `yaml:"value"`
+		} `yaml:"coolDown"`
+		WindowUnit struct {
+			Value string `yaml:"value"`
+		} `yaml:"windowUnit"`
+		RollingWindow struct {
+			Value int `yaml:"value"`
+		} `yaml:"rollingWindow"`
+		CountMode struct {
+			Value string `yaml:"value"`
+		} `yaml:"countMode"`
+		BatchWidth struct {
+			Value int `yaml:"value"`
+		} `yaml:"batchWidth"`
+		Contention struct {
+			Value int `yaml:"value"`
+		} `yaml:"contention"`
+		MempoolInterval struct {
+			Value int `yaml:"value"`
+		} `yaml:"mempoolInterval"`
+		MempoolContent struct {
+			Value bool `yaml:"value"`
+		} `yaml:"mempoolContent"`
+		ReplaceAfter struct {
+			Value int `yaml:"value"`
+		} `yaml:"replaceAfter"`
+		FeeBump struct {
+			Value int `yaml:"value"`
+		} `yaml:"feeBump"`
+		MaxReplacements struct {
+			Value int `yaml:"value"`
+		} `yaml:"maxReplacements"`
+		DrainTimeout struct {
+			Value int `yaml:"value"`
+		} `yaml:"drainTimeout"`
 	} `yaml:"condition"`
 	Multi struct {
 		Value int `yaml:"value"`
 	}
+	Endpoints []Endpoint `yaml:"endpoints"`
+	Strategy  struct {
+		Value string `yaml:"value"`
+	} `yaml:"strategy"`
+	Connections struct {
+		Value int `yaml:"value"`
+	} `yaml:"connections"`
 }
 
 func LoadAddresses(filename string) {
@@ -60,8 +119,38 @@ condition:
     value: 500
   gasLimit:
     value: 21000
+  warmUp:
+    value: 0
+  coolDown:
+    value: 0
+  windowUnit:
+    value: seconds
+  rollingWindow:
+    value: 10
+  countMode:
+    value: transactions
+  batchWidth:
+    value: 10
+  contention:
+    value: 0
+  mempoolInterval:
+    value: 0
+  mempoolContent:
+    value: false
+  replaceAfter:
+    value: 30
+  feeBump:
+    value: 10
+  maxReplacements:
+    value: 3
+  drainTimeout:
+    value: 120
 multi:
   value: 50
+strategy:
+  value: round-robin
+connections:
+  value: 1
 `)
 	_, err := os.Stat(filePath)
 	if os.IsNotExist(err) {
@@ -85,5 +174,125 @@ multi:
 	Rate = config.Condition.Rate.Value
 	Total = config.Condition.Total.Value
 	GasLimit = config.Condition.GasLimit.Value
+	WarmUp = config.Condition.WarmUp.Value
+	CoolDown = config.Condition.CoolDown.Value
+	WindowUnit = config.Condition.WindowUnit.Value
+	RollingWindow = config.Condition.RollingWindow.Value
+	CountMode = config.Condition.CountMode.Value
+	BatchWidth = config.Condition.BatchWidth.Value
+	Contention = min(max(config.Condition.Contention.Value, 0), 100)
+	if BatchWidth <= 0 {
+		BatchWidth = 10
+	}
+	if RollingWindow <= 0 {
+		RollingWindow = 10
+	}
+	MempoolInterval = time.Duration(max(config.Condition.MempoolInterval.Value, 0)) * time.Millisecond
+	MempoolContent = config.Condition.MempoolContent.Value
+	ReplaceAfter = time.Duration(config.Condition.ReplaceAfter.Value) * time.Second
+	FeeBump = config.Condition.FeeBump.Value
+	if FeeBump <= 0 {
+		FeeBump = 10
+	}
+	MaxReplacements = config.Condition.MaxReplacements.Value
+	if MaxReplacements <= 0 {
+		MaxReplacements = 3
+	}
+	DrainTimeout = time.Duration(config.Condition.DrainTimeout.Value) * time.Second
+	if DrainTimeout <= 0 {
+		DrainTimeout = 120 * time.Second
+	}
 	Multi = config.Multi.Value
+	loadEndpoints()
+}
+
+// ReloadAddresses reads only the contract addresses of filename, e.g. after a
+// reset deployed the contracts again. The conditions, which the flags may have
+// overridden, are kept.
+func ReloadAddresses(filename string) {
+	file, err := os.ReadFile(filename)
+	if err != nil {
+		log.Fatalf("failed to read file: %v", err)
+	}
+	var reloaded Config
+	if err = yaml.Unmarshal(file, &reloaded); err != nil {
+		log.Fatalf("failed to unmarshal YAML: %v", err)
+	}
+	config.Contracts = reloaded.Contracts
+	ERC20ADDRESS = common.HexToAddress(config.Contracts.ERC20.Address)
+	ERC721ADDRESS = common.HexToAddress(config.Contracts.ERC721.Address)
+	ERC1155ADDRESS = common.HexToAddress(config.Contracts.ERC1155.Address)
+}
+
+// loadEndpoints uses the configured endpoints, or Host1 to submit and Host2 to
+// observe. Host1 and Host2 are set to the first submit and observe endpoints.
+func loadEndpoints() {
+	Endpoints = config.Endpoints
+	if len(Endpoints) == 0 {
+		Endpoints = []Endpoint{{URL: Host1, Role: "submit"}, {URL: Host2, Role: "observe"}}
+	}
+	submit, observe := "", ""
+	for i := range Endpoints {
+		endpoint := &Endpoints[i]
+		if !endpoint.Submits() && !endpoint.Observes() {
+			log.Fatalf("endpoint %v: unknown role %q", endpoint.URL, endpoint.Role)
+		}
+		if err := endpoint.applyTransport(); err != nil {
+			log.Fatalf("endpoint %v: %v", endpoint.URL, err)
+		}
+		if endpoint.Submits() && submit == "" {
+			submit = endpoint.URL
+		}
+		if endpoint.Observes() && observe == "" {
+			observe = endpoint.URL
+		}
+	}
+	if submit == "" || observe == "" {
+		log.Fatal("endpoints need at least one submit and one observe role")
+	}
+	Host1, Host2 = submit, observe
+
+	Strategy = config.Strategy.Value
+	if Strategy == "" {
+		Strategy = "round-robin"
+	}
+	Connections = max(config.Connections.Value, 1)
+}
+
+// applyTransport fills in the transport of the URL, or rewrites the scheme of
+// a ws or http URL to the configured transport.
+func (e *Endpoint) applyTransport() error {
+	scheme, rest, found := strings.Cut(e.URL, "://")
+	transport := "ipc"
+	if found {
+		switch scheme {
+		case "ws", "wss":
+			transport = "ws"
+		case "http", "https":
+			transport = "http"
+		default:
+			return fmt.Errorf("unsupported scheme %q", scheme)
+		}
+	}
+	if e.Transport == "" || e.Transport == transport {
+		e.Transport = transport
+		return nil
+	}
+	if !found || e.Transport == "ipc" {
+		return fmt.Errorf("transport %v does not match the URL", e.Transport)
+	}
+	secure := strings.HasSuffix(scheme, "s")
+	switch e.Transport {
+	case "ws":
+		scheme = "ws"
+	case "http":
+		scheme = "http"
+	default:
+		return fmt.Errorf("unknown transport %q, expected ws, http or ipc", e.Transport)
+	}
+	if secure {
+		scheme += "s"
+	}
+	e.URL = scheme + "://" + rest
+	return nil
 }
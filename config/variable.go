@@ -10,29 +10,37 @@ import (
 )
 
 var (
-	PrivateKeyHex          []string
-	PrivateKey             []*ecdsa.PrivateKey
-	Result                 map[string]string
-	ChStart                = make(chan time.Time)
-	ChFinish               = make(chan int)
-	ChFailedCount          = make(chan int)
-	ChFileWriteFinish      = make(chan bool)
-	WaitSubscribeBlockHead sync.WaitGroup
+	PrivateKeyHex []string
+	PrivateKey    []*ecdsa.PrivateKey
 
-	ERC20ADDRESS   common.Address
-	ERC721ADDRESS  common.Address
-	ERC1155ADDRESS common.Address
-	Rate           int
-	Total          int
-	GasLimit       uint64
+	ERC20ADDRESS    common.Address
+	ERC721ADDRESS   common.Address
+	ERC1155ADDRESS  common.Address
+	Rate            int
+	Total           int
+	GasLimit        uint64
+	WarmUp          int
+	CoolDown        int
+	WindowUnit      string
+	RollingWindow   int
+	CountMode       string
+	BatchWidth      int
+	Contention      int
+	Verify          bool
+	Propagation     bool
+	Endpoints       []Endpoint
+	Strategy        string
+	Connections     int
+	MempoolInterval time.Duration
+	MempoolContent  bool
+	ReplaceAfter    time.Duration
+	FeeBump         int
+	MaxReplacements int
+	DrainTimeout    time.Duration
 
-	OneEther     = big.NewInt(params.Ether)
-	Start        time.Time
-	Err          error
-	Multi        int
-	TotalDelay   float64
-	MaxTPS       float64
-	MaxBlockTime = 0
-	NonceMutex   sync.Mutex
-	LastNonce    uint64
+	OneEther   = big.NewInt(params.Ether)
+	Err        error
+	Multi      int
+	NonceMutex sync.Mutex
+	LastNonce  uint64
 )
@@ -93,7 +93,7 @@ html_template = """
             </table>
             <img src="{chain_type}_graph.png" alt="Benchmark Result" class="image">
         </div>
-    </div>
+    </div>{base_fee_section}
 </body>
 </html>
 """
@@ -129,6 +129,39 @@ def get_title(file_name):
     }
 
 
+base_fee_template = """
+    <div class="container">
+        <h2>Base Fee and Offered Load</h2>
+        <div class="content">
+            <img src="{chain_type}_base_fee.png" alt="Base Fee" class="image">
+        </div>
+    </div>"""
+
+def plot_base_fee(data, result_dir):
+    # columns 7 and 9 are the base fee in wei and the offered load in tx/s
+    block_height = data[:, 0]
+    base_fee = data[:, 7] / 1e9
+    offered = data[:, 9]
+
+    fig, ax1 = plt.subplots()
+    fee_color = 'dimgrey'
+    ax1.set_xlabel('Block Number')
+    ax1.set_ylabel('Base Fee (gwei)', color=fee_color)
+    ax1.plot(block_height, base_fee, color=fee_color, marker='.', label='Base Fee')
+    ax1.tick_params(axis='y', labelcolor=fee_color)
+
+    ax2 = ax1.twinx()
+    load_color = 'silver'
+    ax2.set_ylabel('Offered Load (tx/s)', color=load_color)
+    ax2.bar(block_height, offered, width=0.6, color=load_color, alpha=0.6, label='Offered Load')
+    ax2.tick_params(axis='y', labelcolor=load_color)
+
+    ax1.legend(loc='upper left', bbox_to_anchor=(0, 1.2))
+    ax2.legend(loc='upper right', bbox_to_anchor=(1, 1.143))
+
+    plt.savefig(os.path.join(result_dir, f'{chain_type}_base_fee.png'), bbox_inches='tight')
+    plt.close()
+
 def get_system_info():
     os_info = f"{platform.system()} {platform.release()}"
     cpu_info = get_cpu_info()['brand_raw']
@@ -179,7 +212,7 @@ def generate_output(system_info):
     data = np.loadtxt(file_name, dtype=int)
 
     if len(data.shape) == 1:
-        data = data.reshape(-1, 5)
+        data = data.reshape(1, -1)
 
     if data.size == 0:
         raise ValueError(f"No data found in {file_name}")
@@ -241,6 +274,11 @@ def generate_output(system_info):
     plt.savefig(output_file_name, bbox_inches='tight')
     plt.close()
 
+    base_fee_section = ''
+    if chain_type in ('ava', 'eth') and data.shape[1] >= 10:
+        plot_base_fee(data, result_dir)
+        base_fee_section = base_fee_template.format(chain_type=chain_type)
+
     html_content = html_template.format(
         title=get_title(file_name)['title'].upper(),
         contract_type=get_title(file_name)['contract_type'].upper(),
@@ -256,6 +294,7 @@ def generate_output(system_info):
         max_latency=max_latency,
         max_tps=max_tps,
         max_transaction_size=max_transaction_size,
+        base_fee_section=base_fee_section,
     )
 
     html_file_name = os.path.join(result_dir, f'report_{chain_type}.html')